@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// calendarEntry is a race as remembered between runs
+type calendarEntry struct {
+	RaceID   string `json:"raceId"`
+	RaceName string `json:"raceName"`
+	Round    int    `json:"round"`
+	Date     string `json:"date"`
+	Time     string `json:"time"`
+}
+
+// savedCalendar is the last-seen calendar for a season
+type savedCalendar struct {
+	Season int             `json:"season"`
+	Races  []calendarEntry `json:"races"`
+}
+
+// newSavedCalendar converts a fetched calendar to the form kept between runs
+func newSavedCalendar(calendar *SeasonResponse) savedCalendar {
+	saved := savedCalendar{Season: calendar.Season}
+	for i, race := range calendar.Races {
+		saved.Races = append(saved.Races, calendarEntry{
+			RaceID:   race.RaceID,
+			RaceName: race.RaceName,
+			Round:    i + 1,
+			Date:     race.Schedule.Race.Date,
+			Time:     race.Schedule.Race.Time,
+		})
+	}
+	return saved
+}
+
+// loadSavedCalendar reads the last-seen calendar, returning false if there isn't one yet
+func loadSavedCalendar() (savedCalendar, bool, error) {
+	var saved savedCalendar
+	ok, err := loadState("calendar.json", "saved calendar", &saved)
+	return saved, ok, err
+}
+
+// saveCalendar writes the calendar for the next run to diff against
+func saveCalendar(saved savedCalendar) error {
+	return saveState("calendar.json", "calendar", saved)
+}
+
+// calendarChange is a race added to, removed from or moved within the calendar
+type calendarChange struct {
+	Kind string // "added", "removed" or "moved"
+	Old  *calendarEntry
+	New  *calendarEntry
+}
+
+// String describes the change, e.g. "Japanese Grand Prix 2025 moved from R3 2025-04-06 to R4 2025-04-13"
+func (c calendarChange) String() string {
+	switch c.Kind {
+	case "added":
+		return fmt.Sprintf("%s added as R%d on %s", c.New.RaceName, c.New.Round, c.New.Date)
+	case "removed":
+		return fmt.Sprintf("%s (R%d on %s) removed", c.Old.RaceName, c.Old.Round, c.Old.Date)
+	default:
+		return fmt.Sprintf("%s moved from R%d on %s to R%d on %s",
+			c.New.RaceName, c.Old.Round, sessionLabel(*c.Old), c.New.Round, sessionLabel(*c.New))
+	}
+}
+
+// sessionLabel returns an entry's date, with its time if it has one
+func sessionLabel(entry calendarEntry) string {
+	if entry.Time == "" {
+		return entry.Date
+	}
+	return entry.Date + " " + entry.Time
+}
+
+// diffCalendars lists the races added, removed or rescheduled between two calendars of the same season.
+// Rounds renumbered only because another race was added or removed aren't reported.
+func diffCalendars(old, new savedCalendar) []calendarChange {
+	if old.Season != new.Season {
+		return nil
+	}
+
+	oldRaces := map[string]*calendarEntry{}
+	for i := range old.Races {
+		oldRaces[old.Races[i].RaceID] = &old.Races[i]
+	}
+	newRaces := map[string]bool{}
+
+	var changes []calendarChange
+	for i := range new.Races {
+		entry := &new.Races[i]
+		newRaces[entry.RaceID] = true
+		previous, ok := oldRaces[entry.RaceID]
+		if !ok {
+			changes = append(changes, calendarChange{Kind: "added", New: entry})
+		} else if previous.Date != entry.Date || previous.Time != entry.Time {
+			changes = append(changes, calendarChange{Kind: "moved", Old: previous, New: entry})
+		}
+	}
+	for i := range old.Races {
+		if !newRaces[old.Races[i].RaceID] {
+			changes = append(changes, calendarChange{Kind: "removed", Old: &old.Races[i]})
+		}
+	}
+	return changes
+}
+
+// checkCalendarChanges diffs the calendar against the last-seen one, logging a warning for each change.
+// It doesn't save the calendar, so changes are reported again until reportCalendarChanges saves it
+func checkCalendarChanges(calendar *SeasonResponse) []calendarChange {
+	if stateDir == "" || calendar == nil {
+		return nil
+	}
+
+	previous, ok, err := loadSavedCalendar()
+	if err != nil {
+		log.Printf("Error loading saved calendar, starting afresh: %v", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	changes := diffCalendars(previous, newSavedCalendar(calendar))
+	for _, change := range changes {
+		log.Printf("WARNING: calendar change: %s", change)
+	}
+	return changes
+}
+
+// reportCalendarChanges posts the calendar changes if notice is set, then saves the calendar as last seen.
+// A changed calendar is only saved once the notice has been posted, so a failed post or a run without
+// notices leaves the changes for the next run
+func reportCalendarChanges(data *TopicData, notice bool, opts PublishOptions) {
+	if stateDir == "" || !snapshot.live() || data.Calendar == nil {
+		return
+	}
+	if len(data.CalendarChanges) > 0 {
+		if !notice {
+			return
+		}
+		if err := postCalendarNotice(data, opts); err != nil {
+			log.Printf("Error posting calendar notice, keeping the changes for the next run: %v", err)
+			return
+		}
+	}
+
+	if err := saveCalendar(newSavedCalendar(&SeasonResponse{Season: data.Season, Races: data.Calendar})); err != nil {
+		log.Printf("Error saving calendar: %v", err)
+	}
+}
+
+// calendarNotice builds the channel message announcing calendar changes
+func calendarNotice(season int, changes []calendarChange) slackMessage {
+	text := fmt.Sprintf(":calendar: The %d F1 calendar has changed:", season)
+	for _, change := range changes {
+		text += "\n• " + change.String()
+	}
+	return slackMessage{Text: text}
+}
+
+// postCalendarNotice posts the calendar changes to the channel with chat.postMessage
+func postCalendarNotice(data *TopicData, opts PublishOptions) error {
+	client, err := channelClient(opts)
+	if err != nil {
+		return err
+	}
+	if err := client.PostMessage(opts.Channel, calendarNotice(data.Season, data.CalendarChanges)); err != nil {
+		return err
+	}
+	log.Printf("Posted calendar notice to %s", opts.Channel)
+	return nil
+}
+
+// calendarCommand runs the calendar subcommands
+func calendarCommand(args []string) error {
+	if len(args) == 0 || args[0] != "diff" {
+		return errors.New("usage: calendar diff")
+	}
+
+	fs := flag.NewFlagSet("calendar diff", flag.ExitOnError)
+	save := fs.Bool("save", false, "Save the current calendar as last seen after showing the diff")
+	fs.Parse(args[1:])
+
+	if stateDir == "" {
+		return errors.New("no state directory, set -state-dir")
+	}
+	calendar, err := fetchCalendar(0)
+	if err != nil {
+		return err
+	}
+	current := newSavedCalendar(calendar)
+
+	previous, ok, err := loadSavedCalendar()
+	if err != nil {
+		return err
+	}
+	switch {
+	case !ok:
+		fmt.Printf("No saved calendar in %s yet\n", stateDir)
+	case previous.Season != current.Season:
+		fmt.Printf("Saved calendar is for %d, the API is on %d\n", previous.Season, current.Season)
+	default:
+		changes := diffCalendars(previous, current)
+		if len(changes) == 0 {
+			fmt.Printf("No changes to the %d calendar\n", current.Season)
+		}
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+	}
+
+	if *save {
+		return saveCalendar(current)
+	}
+	return nil
+}
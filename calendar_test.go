@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// testCalendar returns a three race calendar as fetched from the API
+func testCalendar() *SeasonResponse {
+	return &SeasonResponse{Season: 2025, Races: []Race{
+		{RaceID: "australian_2025", RaceName: "Australian Grand Prix 2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-03-16", Time: "04:00:00Z"}}},
+		{RaceID: "chinese_2025", RaceName: "Chinese Grand Prix 2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-03-23", Time: "07:00:00Z"}}},
+		{RaceID: "japanese_2025", RaceName: "Japanese Grand Prix 2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-04-06", Time: "05:00:00Z"}}},
+	}}
+}
+
+// useStateDir points stateDir at a temporary directory for the rest of the test
+func useStateDir(t *testing.T) {
+	t.Helper()
+	stateDir = t.TempDir()
+	t.Cleanup(func() { stateDir = "" })
+}
+
+func TestDiffCalendars(t *testing.T) {
+	old := newSavedCalendar(testCalendar())
+
+	changed := testCalendar()
+	changed.Races = append(changed.Races[:1], changed.Races[2:]...)
+	changed.Races[1].Schedule.Race.Date = "2025-04-13"
+	changed.Races = append(changed.Races, Race{RaceID: "bahrain_2025", RaceName: "Bahrain Grand Prix 2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-04-20"}}})
+
+	var got []string
+	for _, change := range diffCalendars(old, newSavedCalendar(changed)) {
+		got = append(got, change.String())
+	}
+	expected := []string{
+		"Japanese Grand Prix 2025 moved from R3 on 2025-04-06 05:00:00Z to R2 on 2025-04-13 05:00:00Z",
+		"Bahrain Grand Prix 2025 added as R3 on 2025-04-20",
+		"Chinese Grand Prix 2025 (R2 on 2025-03-23) removed",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected changes:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestDiffCalendarsUnchanged(t *testing.T) {
+	if changes := diffCalendars(newSavedCalendar(testCalendar()), newSavedCalendar(testCalendar())); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// A new season isn't a change to the old one
+	next := testCalendar()
+	next.Season = 2026
+	if changes := diffCalendars(newSavedCalendar(testCalendar()), newSavedCalendar(next)); len(changes) != 0 {
+		t.Errorf("Expected no changes across seasons, got %v", changes)
+	}
+}
+
+// calendarRun checks a fetched calendar for changes as a run does, then reports them
+func calendarRun(calendar *SeasonResponse, notice bool) []calendarChange {
+	data := &TopicData{Season: calendar.Season, Calendar: calendar.Races, CalendarChanges: checkCalendarChanges(calendar)}
+	reportCalendarChanges(data, notice, PublishOptions{Channel: "C123", Token: "xoxb-test"})
+	return data.CalendarChanges
+}
+
+func TestCheckCalendarChanges(t *testing.T) {
+	useStateDir(t)
+	newFakeSlack(t)
+
+	if changes := calendarRun(testCalendar(), false); len(changes) != 0 {
+		t.Errorf("The first run has nothing to diff against, got %v", changes)
+	}
+
+	moved := testCalendar()
+	moved.Races[2].Schedule.Race.Time = "06:00:00Z"
+	changes := calendarRun(moved, false)
+	if len(changes) != 1 || changes[0].Kind != "moved" {
+		t.Errorf("Expected the Japanese Grand Prix to have moved, got %v", changes)
+	}
+
+	// Without a notice the change is reported again, until one is posted
+	if changes := calendarRun(moved, true); len(changes) != 1 {
+		t.Errorf("Unposted changes should carry over to the next run, got %v", changes)
+	}
+	if changes := calendarRun(moved, true); len(changes) != 0 {
+		t.Errorf("Changes should only be posted once, got %v", changes)
+	}
+}
+
+func TestCalendarChangesKeptAfterFailedNotice(t *testing.T) {
+	useStateDir(t)
+	fake := newFakeSlack(t)
+	calendarRun(testCalendar(), true)
+
+	moved := testCalendar()
+	moved.Races[2].Schedule.Race.Date = "2025-04-13"
+	fake.responses["chat.postMessage"] = `{"ok":false,"error":"channel_not_found"}`
+	if changes := calendarRun(moved, true); len(changes) != 1 {
+		t.Fatalf("Expected the Japanese Grand Prix to have moved, got %v", changes)
+	}
+
+	delete(fake.responses, "chat.postMessage")
+	if changes := calendarRun(moved, true); len(changes) != 1 || changes[0].Kind != "moved" {
+		t.Errorf("A failed notice should leave the change for the next run, got %v", changes)
+	}
+	if !strings.Contains(string(fake.requests["chat.postMessage"]), "Japanese Grand Prix 2025 moved") {
+		t.Errorf("The next run should post the change, got %s", fake.requests["chat.postMessage"])
+	}
+}
+
+func TestCheckCalendarChangesWithoutStateDir(t *testing.T) {
+	if changes := checkCalendarChanges(testCalendar()); changes != nil {
+		t.Errorf("Nothing should be kept without a state directory, got %v", changes)
+	}
+}
+
+func TestPostCalendarNotice(t *testing.T) {
+	fake := newFakeSlack(t)
+
+	data := testTopicData()
+	old := newSavedCalendar(testCalendar())
+	data.CalendarChanges = diffCalendars(old, savedCalendar{Season: 2025, Races: old.Races[:2]})
+
+	if err := postCalendarNotice(data, PublishOptions{Channel: "C123", Token: "xoxb-test"}); err != nil {
+		t.Fatalf("postCalendarNotice failed: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &msg); err != nil {
+		t.Fatalf("chat.postMessage body should be JSON: %v", err)
+	}
+	expected := ":calendar: The 2025 F1 calendar has changed:\n• Japanese Grand Prix 2025 (R3 on 2025-04-06) removed"
+	if msg.Channel != "C123" || msg.Text != expected {
+		t.Errorf("Unexpected notice: %+v", msg)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// CircuitResponse is the F1 API response for a single circuit
+type CircuitResponse struct {
+	API     string    `json:"api"`
+	URL     string    `json:"url"`
+	Circuit []Circuit `json:"circuit"`
+}
+
+// complete reports whether the circuit has everything the circuit card shows
+func (c Circuit) complete() bool {
+	return c.Length > 0 && c.Laps > 0 && c.Laprecord != "" && c.FastestLapDriverID != "" && c.FirstParticipationYear > 0
+}
+
+// fetchCircuit gets a circuit's details from the circuits endpoint
+func fetchCircuit(circuitID string) (*Circuit, error) {
+	var circuitResp CircuitResponse
+	if err := fetchAPI("/circuits/"+circuitID, "circuit "+circuitID, &circuitResp); err != nil {
+		return nil, err
+	}
+	if len(circuitResp.Circuit) == 0 {
+		return nil, fmt.Errorf("%w: circuit %s", errNoData, circuitID)
+	}
+	return &circuitResp.Circuit[0], nil
+}
+
+// fillCircuit fetches the circuit's details and fills in any fields the next-race payload left empty
+func fillCircuit(circuit *Circuit) error {
+	if circuit.CircuitID == "" {
+		return fmt.Errorf("race has no circuit ID")
+	}
+	details, err := fetchCircuit(circuit.CircuitID)
+	if err != nil {
+		return err
+	}
+
+	if circuit.Length == 0 {
+		circuit.Length = details.Length
+	}
+	if circuit.Laps == 0 {
+		circuit.Laps = details.Laps
+	}
+	if circuit.Laprecord == "" {
+		circuit.Laprecord = details.Laprecord
+		circuit.FastestLapDriverID = details.FastestLapDriverID
+		circuit.FastestLapYear = details.FastestLapYear
+	}
+	if circuit.FastestLapDriverID == "" {
+		circuit.FastestLapDriverID = details.FastestLapDriverID
+	}
+	if circuit.FastestLapYear == 0 {
+		circuit.FastestLapYear = details.FastestLapYear
+	}
+	if circuit.FirstParticipationYear == 0 {
+		circuit.FirstParticipationYear = details.FirstParticipationYear
+	}
+	return nil
+}
+
+// fetchCircuitDetails fills in the next race's circuit card when the next-race payload lacks it.
+// Only the detailed output shows the card, so it's called for that alone to keep the topic to fewer requests
+func fetchCircuitDetails(data *TopicData) {
+	if data.NextRaceErr != nil || data.NextRace == nil || data.NextRace.Circuit.complete() {
+		return
+	}
+	if err := fillCircuit(&data.NextRace.Circuit); err != nil {
+		log.Printf("Error fetching circuit details: %v", err)
+	}
+}
+
+// formatLapRecord formats a lap record the API sends as "1:30:983" like a lap time, "1:30.983"
+func formatLapRecord(record string) string {
+	if strings.Count(record, ":") == 2 {
+		i := strings.LastIndex(record, ":")
+		return record[:i] + "." + record[i+1:]
+	}
+	return record
+}
+
+// driverName returns the full name of a driver in the standings, falling back to their ID
+func driverName(data *TopicData, driverID string) string {
+	for _, driver := range data.Drivers {
+		if driver.DriverID == driverID {
+			return driver.Driver.Name + " " + driver.Driver.Surname
+		}
+	}
+	return driverID
+}
+
+// writeCircuitCard writes the circuit's length, laps, race distance, lap record and first Grand Prix
+// for the detailed output, skipping anything the API didn't provide
+func writeCircuitCard(sb *strings.Builder, data *TopicData, circuit Circuit) {
+	var lengths []string
+	if circuit.Length > 0 {
+		lengths = append(lengths, fmt.Sprintf("%.3f km", circuit.Length))
+	}
+	if circuit.Laps > 0 {
+		lengths = append(lengths, plural(circuit.Laps, "lap"))
+	}
+	if circuit.Length > 0 && circuit.Laps > 0 {
+		lengths = append(lengths, fmt.Sprintf("%.3f km race distance", circuit.Length*float64(circuit.Laps)))
+	}
+	if len(lengths) > 0 {
+		sb.WriteString(fmt.Sprintf("  Length: %s\n", strings.Join(lengths, ", ")))
+	}
+
+	if circuit.Laprecord != "" {
+		var holder []string
+		if circuit.FastestLapDriverID != "" {
+			holder = append(holder, driverName(data, circuit.FastestLapDriverID))
+		}
+		if circuit.FastestLapYear > 0 {
+			holder = append(holder, fmt.Sprint(circuit.FastestLapYear))
+		}
+		record := formatLapRecord(circuit.Laprecord)
+		if len(holder) > 0 {
+			record += fmt.Sprintf(" (%s)", strings.Join(holder, ", "))
+		}
+		sb.WriteString(fmt.Sprintf("  Lap record: %s\n", record))
+	}
+
+	if circuit.FirstParticipationYear > 0 {
+		sb.WriteString(fmt.Sprintf("  First Grand Prix: %d\n", circuit.FirstParticipationYear))
+	}
+}
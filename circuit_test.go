@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextRendererCircuitCard(t *testing.T) {
+	data := testTopicData()
+	data.Drivers = append(data.Drivers, DriverStanding{DriverID: "hamilton", Driver: Driver{Name: "Lewis", Surname: "Hamilton"}})
+	data.NextRace.Circuit.Length = 5.807
+	data.NextRace.Circuit.Laps = 53
+	data.NextRace.Circuit.Laprecord = "1:30:983"
+	data.NextRace.Circuit.FastestLapDriverID = "hamilton"
+	data.NextRace.Circuit.FastestLapYear = 2019
+	data.NextRace.Circuit.FirstParticipationYear = 1987
+
+	output := textRenderer{}.Render(data)
+	expected := "Circuit: Suzuka International Racing Course\n" +
+		"  Length: 5.807 km, 53 laps, 307.771 km race distance\n" +
+		"  Lap record: 1:30.983 (Lewis Hamilton, 2019)\n" +
+		"  First Grand Prix: 1987\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Output should contain the circuit card %q, got:\n%s", expected, output)
+	}
+}
+
+func TestTextRendererCircuitCardPartial(t *testing.T) {
+	data := testTopicData()
+	data.NextRace.Circuit.Laps = 53
+
+	output := textRenderer{}.Render(data)
+	if !strings.Contains(output, "Circuit: Suzuka International Racing Course\n  Length: 53 laps\nDate:") {
+		t.Errorf("Output should only show the known circuit details, got:\n%s", output)
+	}
+}
+
+func TestFillCircuit(t *testing.T) {
+	newFakeF1API(t, map[string]string{
+		"/circuits/suzuka": `{"circuit": [{"circuitId": "suzuka", "circuitName": "Suzuka International Racing Course", "length": 5.807, "laps": 53,
+			"lapRecord": "1:30:983", "fastestLapDriverId": "hamilton", "fastestLapYear": 2019, "firstParticipationYear": 1987}]}`,
+	})
+
+	circuit := Circuit{CircuitID: "suzuka", Laps: 52}
+	if err := fillCircuit(&circuit); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := Circuit{CircuitID: "suzuka", Length: 5.807, Laps: 52, Laprecord: "1:30:983",
+		FastestLapDriverID: "hamilton", FastestLapYear: 2019, FirstParticipationYear: 1987}
+	if circuit != want {
+		t.Errorf("Expected the payload's laps kept and the rest filled in, got %+v", circuit)
+	}
+
+	unknown := Circuit{CircuitID: "nowhere"}
+	if err := fillCircuit(&unknown); !errors.Is(err, errNoData) {
+		t.Errorf("Expected no data found for an unknown circuit, got %v", err)
+	}
+}
+
+func TestFetchCircuitDetailsOnlyForDetailedOutput(t *testing.T) {
+	newFakeF1API(t, map[string]string{
+		"/current/next": `{"season": 2025, "round": 3, "race": [{"raceId": "japanese_2025", "schedule": {"race": {"date": "2099-04-06"}},
+			"circuit": {"circuitId": "suzuka"}}]}`,
+		"/circuits/suzuka": `{"circuit": [{"circuitId": "suzuka", "length": 5.807, "laps": 53}]}`,
+	})
+
+	// The topic doesn't show the circuit card, so fetching the data leaves it alone
+	data := fetchTopicData()
+	if data.NextRace == nil || data.NextRace.Circuit.Laps != 0 {
+		t.Fatalf("fetchTopicData shouldn't fetch circuit details, got %+v", data.NextRace)
+	}
+
+	fetchCircuitDetails(data)
+	if data.NextRace.Circuit.Laps != 53 {
+		t.Errorf("Expected the circuit details for the detailed output, got %+v", data.NextRace.Circuit)
+	}
+}
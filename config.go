@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds user settings loaded from the JSON file given with -config
+type Config struct {
+	// RaceNames overrides short race names, keyed by race ID (e.g. "japanese_2025"),
+	// race ID without the year (e.g. "japanese") or circuit ID (e.g. "suzuka")
+	RaceNames map[string]string `json:"raceNames,omitempty"`
+
+	// TeamEmojis, TeamAbbrs and DriverEmojis add to or override the built-in maps, keyed by team or driver ID
+	TeamEmojis   map[string]string `json:"teamEmojis,omitempty"`
+	TeamAbbrs    map[string]string `json:"teamAbbrs,omitempty"`
+	DriverEmojis map[string]string `json:"driverEmojis,omitempty"`
+
+	// PreseasonTesting is the first day of pre-season testing (YYYY-MM-DD), counted down to in the off-season
+	PreseasonTesting string `json:"preseasonTesting,omitempty"`
+
+	// ShowFrontRow shows both front row drivers in the topic between qualifying and the race, rather than just the pole
+	ShowFrontRow bool `json:"showFrontRow,omitempty"`
+
+	// ShowClosestBattle adds the teammates closest on points to the topic
+	ShowClosestBattle bool `json:"showClosestBattle,omitempty"`
+
+	// Reminders are the offsets before each session to post a reminder at, keyed by session
+	// (fp1, fp2, fp3, sprint-qualy, sprint, qualy or race), e.g. {"qualy": ["1h"], "race": ["30m"]}
+	Reminders map[string][]string `json:"reminders,omitempty"`
+
+	// Fantasy configures the fantasy league segment at the end of the Slack topic
+	Fantasy FantasyConfig `json:"fantasy,omitempty"`
+}
+
+// config is the loaded configuration, empty unless -config is given
+var config Config
+
+// loadConfig reads and parses a JSON config file
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("error reading config: %v", err)
+	}
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+	return cfg, nil
+}
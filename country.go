@@ -0,0 +1,131 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// country is an entry in the country registry
+type country struct {
+	// code is the lowercase ISO 3166-1 alpha-2 code
+	code string
+	// names are the country names and API variants that resolve to this country
+	names []string
+	// demonyms are the nationality adjectives that resolve to this country, e.g. "Dutch"
+	demonyms []string
+}
+
+// countries is the registry used to resolve race countries and driver nationalities to ISO codes.
+// Demonyms cover the current grid and historical drivers, including the API's hyphenated forms.
+var countries = []country{
+	{"ae", []string{"United Arab Emirates", "UAE", "Abu Dhabi"}, []string{"Emirati"}},
+	{"ar", []string{"Argentina"}, []string{"Argentine", "Argentinian", "Argentine-Italian"}},
+	{"at", []string{"Austria"}, []string{"Austrian"}},
+	{"au", []string{"Australia"}, []string{"Australian"}},
+	{"az", []string{"Azerbaijan", "Baku"}, []string{"Azerbaijani"}},
+	{"be", []string{"Belgium"}, []string{"Belgian"}},
+	{"bh", []string{"Bahrain"}, []string{"Bahraini"}},
+	{"br", []string{"Brazil", "São Paulo", "Sao Paulo"}, []string{"Brazilian"}},
+	{"ca", []string{"Canada"}, []string{"Canadian"}},
+	{"ch", []string{"Switzerland"}, []string{"Swiss"}},
+	{"cl", []string{"Chile"}, []string{"Chilean"}},
+	{"cn", []string{"China"}, []string{"Chinese"}},
+	{"co", []string{"Colombia"}, []string{"Colombian"}},
+	{"cz", []string{"Czech Republic", "Czechia"}, []string{"Czech"}},
+	{"de", []string{"Germany", "West Germany", "East Germany"}, []string{"German", "West German", "East German"}},
+	{"dk", []string{"Denmark"}, []string{"Danish", "Dane"}},
+	{"ee", []string{"Estonia"}, []string{"Estonian"}},
+	{"es", []string{"Spain"}, []string{"Spanish", "Spaniard"}},
+	{"fi", []string{"Finland"}, []string{"Finnish", "Finn"}},
+	{"fr", []string{"France"}, []string{"French"}},
+	{"gb", []string{"Great Britain", "United Kingdom", "UK", "England", "Scotland", "Wales", "Northern Ireland"}, []string{"British", "English", "Scottish", "Welsh"}},
+	{"hu", []string{"Hungary"}, []string{"Hungarian"}},
+	{"id", []string{"Indonesia"}, []string{"Indonesian"}},
+	{"ie", []string{"Ireland"}, []string{"Irish"}},
+	{"il", []string{"Israel"}, []string{"Israeli"}},
+	{"in", []string{"India"}, []string{"Indian"}},
+	{"it", []string{"Italy", "Emilia Romagna"}, []string{"Italian"}},
+	{"jp", []string{"Japan"}, []string{"Japanese"}},
+	{"kr", []string{"South Korea", "Korea"}, []string{"South Korean", "Korean"}},
+	{"li", []string{"Liechtenstein"}, []string{"Liechtensteiner"}},
+	{"ma", []string{"Morocco"}, []string{"Moroccan"}},
+	{"mc", []string{"Monaco"}, []string{"Monegasque", "Monégasque"}},
+	{"mx", []string{"Mexico", "Mexico City"}, []string{"Mexican"}},
+	{"my", []string{"Malaysia"}, []string{"Malaysian"}},
+	{"nl", []string{"Netherlands", "The Netherlands", "Holland"}, []string{"Dutch"}},
+	{"nz", []string{"New Zealand"}, []string{"New Zealander", "Kiwi"}},
+	{"pl", []string{"Poland"}, []string{"Polish"}},
+	{"pt", []string{"Portugal"}, []string{"Portuguese"}},
+	{"qa", []string{"Qatar"}, []string{"Qatari"}},
+	{"ru", []string{"Russia", "Russian Federation"}, []string{"Russian"}},
+	{"sa", []string{"Saudi Arabia", "Saudi"}, []string{"Saudi Arabian"}},
+	{"se", []string{"Sweden"}, []string{"Swedish", "Swede"}},
+	{"sg", []string{"Singapore"}, []string{"Singaporean"}},
+	{"sm", []string{"San Marino"}, []string{"Sammarinese"}},
+	{"th", []string{"Thailand"}, []string{"Thai"}},
+	{"tr", []string{"Turkey", "Türkiye"}, []string{"Turkish"}},
+	{"us", []string{"United States", "United States of America", "USA", "US", "Miami", "Las Vegas"}, []string{"American", "American-Italian"}},
+	{"uy", []string{"Uruguay"}, []string{"Uruguayan"}},
+	{"ve", []string{"Venezuela"}, []string{"Venezuelan"}},
+	{"za", []string{"South Africa"}, []string{"South African"}},
+	{"zw", []string{"Zimbabwe", "Rhodesia"}, []string{"Zimbabwean", "Rhodesian"}},
+}
+
+// countryCodes maps every registry name and demonym, lowercased, to its ISO code
+var countryCodes = func() map[string]string {
+	codes := map[string]string{}
+	for _, c := range countries {
+		for _, name := range c.allNames() {
+			codes[strings.ToLower(name)] = c.code
+		}
+	}
+	return codes
+}()
+
+// allNames returns the country's names followed by its demonyms
+func (c country) allNames() []string {
+	return append(append([]string(nil), c.names...), c.demonyms...)
+}
+
+// countryNamesByLength holds every registry name, longest first, for matching inside race names
+var countryNamesByLength = func() []string {
+	var names []string
+	for _, c := range countries {
+		names = append(names, c.allNames()...)
+	}
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return names
+}()
+
+// lookupCountry returns the ISO code for a country name, demonym or API variant, ignoring case
+func lookupCountry(name string) (string, bool) {
+	code, ok := countryCodes[strings.ToLower(strings.TrimSpace(name))]
+	return code, ok
+}
+
+// raceCountryCode returns the ISO code for a race's country, or "" if it can't be resolved
+func raceCountryCode(race *Race) string {
+	if code, ok := lookupCountry(race.Country); ok {
+		return code
+	}
+
+	// Fall back to a country name or demonym in the race name, e.g. "Japanese Grand Prix"
+	raceName := " " + strings.ToLower(race.RaceName) + " "
+	for _, name := range countryNamesByLength {
+		if strings.Contains(raceName, " "+strings.ToLower(name)+" ") {
+			return countryCodes[strings.ToLower(name)]
+		}
+	}
+
+	warnOnce("no country mapping for race country %q, add it to the country registry", race.Country)
+	return ""
+}
+
+// driverCountryCode returns the ISO code for a driver's nationality, or "" if it can't be resolved
+func driverCountryCode(driver Driver) string {
+	if code, ok := lookupCountry(driver.Nationality); ok {
+		return code
+	}
+	warnOnce("no country mapping for driver nationality %q, add it to the country registry", driver.Nationality)
+	return ""
+}
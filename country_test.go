@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRaceCountryCode(t *testing.T) {
+	tests := []struct {
+		race Race
+		want string
+	}{
+		{Race{RaceName: "Lenovo Japanese Grand Prix 2025", Country: "Japan"}, "jp"},
+		{Race{RaceName: "Lenovo Japanese Grand Prix 2025"}, "jp"},
+		{Race{RaceName: "Heineken Chinese Grand Prix 2025"}, "cn"},
+		{Race{RaceName: "Qatar Airways Qatar Grand Prix 2025", Country: "Qatar"}, "qa"},
+		{Race{RaceName: "STC Saudi Arabian Grand Prix 2025", Country: "Saudi Arabia"}, "sa"},
+		{Race{RaceName: "Qatar Airways Azerbaijan Grand Prix 2025", Country: "Azerbaijan"}, "az"},
+		{Race{RaceName: "Etihad Airways Abu Dhabi Grand Prix 2025", Country: "United Arab Emirates"}, "ae"},
+		{Race{RaceName: "Formula 1 Crypto.com Miami Grand Prix 2025", Country: "USA"}, "us"},
+		{Race{RaceName: "Formula 1 Qatar Airways British Grand Prix 2025", Country: "Great Britain"}, "gb"},
+		{Race{RaceName: "Gran Premio Warsteiner di San Marino 2006", Country: "San Marino"}, "sm"},
+		{Race{RaceName: "Gran Premio Warsteiner di San Marino 2006"}, "sm"},
+		{Race{RaceName: "Formula 1 AWS Gran Premio del Made in Italy e dell'Emilia-Romagna 2025", Country: "Italy"}, "it"},
+		{Race{RaceName: "Grand Prix of Atlantis 2025", Country: "Atlantis"}, ""},
+	}
+	for _, test := range tests {
+		if got := raceCountryCode(&test.race); got != test.want {
+			t.Errorf("raceCountryCode(%q, %q) = %q, want %q", test.race.RaceName, test.race.Country, got, test.want)
+		}
+	}
+}
+
+func TestDriverCountryCode(t *testing.T) {
+	tests := map[string]string{
+		// Country names, as the API returns nationalities
+		"Great Britain": "gb",
+		"Netherlands":   "nl",
+		"Monaco":        "mc",
+		"Thailand":      "th",
+		"New Zealand":   "nz",
+		"monaco":        "mc",
+		" Australia ":   "au",
+
+		// Demonyms, the fallback for nationalities recorded that way, across the current grid and historical drivers
+		"British":           "gb",
+		"Dutch":             "nl",
+		"Monegasque":        "mc",
+		"Australian":        "au",
+		"Italian":           "it",
+		"Thai":              "th",
+		"Spanish":           "es",
+		"Canadian":          "ca",
+		"German":            "de",
+		"Brazilian":         "br",
+		"French":            "fr",
+		"Argentine":         "ar",
+		"Argentinian":       "ar",
+		"New Zealander":     "nz",
+		"Japanese":          "jp",
+		"Finnish":           "fi",
+		"Mexican":           "mx",
+		"Danish":            "dk",
+		"Chinese":           "cn",
+		"American":          "us",
+		"Austrian":          "at",
+		"Belgian":           "be",
+		"Swiss":             "ch",
+		"Swedish":           "se",
+		"Polish":            "pl",
+		"Russian":           "ru",
+		"Venezuelan":        "ve",
+		"Colombian":         "co",
+		"Indian":            "in",
+		"Indonesian":        "id",
+		"Malaysian":         "my",
+		"Irish":             "ie",
+		"Portuguese":        "pt",
+		"Czech":             "cz",
+		"Hungarian":         "hu",
+		"Chilean":           "cl",
+		"Uruguayan":         "uy",
+		"South African":     "za",
+		"Rhodesian":         "zw",
+		"Liechtensteiner":   "li",
+		"Moroccan":          "ma",
+		"East German":       "de",
+		"American-Italian":  "us",
+		"Argentine-Italian": "ar",
+		"monegasque":        "mc",
+		" Australian ":      "au",
+	}
+	for nationality, want := range tests {
+		if got := driverCountryCode(Driver{Nationality: nationality}); got != want {
+			t.Errorf("driverCountryCode(%q) = %q, want %q", nationality, got, want)
+		}
+	}
+}
+
+func TestUnmappedCountryWarning(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	driver := Driver{Nationality: "Ruritanian"}
+	if got := driverCountryCode(driver); got != "" {
+		t.Errorf("Expected no code for an unmapped nationality, got %q", got)
+	}
+	driverCountryCode(driver)
+
+	if count := strings.Count(logs.String(), `WARNING: no country mapping for driver nationality "Ruritanian"`); count != 1 {
+		t.Errorf("Expected exactly one warning, got %d:\n%s", count, logs.String())
+	}
+}
+
+func TestCountryRegistryHasNoDuplicates(t *testing.T) {
+	seen := map[string]string{}
+	for _, c := range countries {
+		if len(c.code) != 2 || strings.ToLower(c.code) != c.code {
+			t.Errorf("Country code %q should be a lowercase ISO 3166-1 alpha-2 code", c.code)
+		}
+		for _, name := range c.allNames() {
+			key := strings.ToLower(name)
+			if other, exists := seen[key]; exists {
+				t.Errorf("%q maps to both %s and %s", name, other, c.code)
+			}
+			seen[key] = c.code
+		}
+	}
+}
+
+// gridNationalitiesJSON is a trimmed drivers-championship payload for the current grid, with nationalities
+// as the country names the API returns
+const gridNationalitiesJSON = `{"season":2025,"drivers_championship":[
+{"driverId":"norris","driver":{"shortName":"NOR","nationality":"Great Britain"}},
+{"driverId":"piastri","driver":{"shortName":"PIA","nationality":"Australia"}},
+{"driverId":"max_verstappen","driver":{"shortName":"VER","nationality":"Netherlands"}},
+{"driverId":"russell","driver":{"shortName":"RUS","nationality":"Great Britain"}},
+{"driverId":"leclerc","driver":{"shortName":"LEC","nationality":"Monaco"}},
+{"driverId":"hamilton","driver":{"shortName":"HAM","nationality":"Great Britain"}},
+{"driverId":"antonelli","driver":{"shortName":"ANT","nationality":"Italy"}},
+{"driverId":"albon","driver":{"shortName":"ALB","nationality":"Thailand"}},
+{"driverId":"sainz","driver":{"shortName":"SAI","nationality":"Spain"}},
+{"driverId":"alonso","driver":{"shortName":"ALO","nationality":"Spain"}},
+{"driverId":"stroll","driver":{"shortName":"STR","nationality":"Canada"}},
+{"driverId":"hulkenberg","driver":{"shortName":"HUL","nationality":"Germany"}},
+{"driverId":"bortoleto","driver":{"shortName":"BOR","nationality":"Brazil"}},
+{"driverId":"ocon","driver":{"shortName":"OCO","nationality":"France"}},
+{"driverId":"bearman","driver":{"shortName":"BEA","nationality":"Great Britain"}},
+{"driverId":"gasly","driver":{"shortName":"GAS","nationality":"France"}},
+{"driverId":"colapinto","driver":{"shortName":"COL","nationality":"Argentina"}},
+{"driverId":"lawson","driver":{"shortName":"LAW","nationality":"New Zealand"}},
+{"driverId":"hadjar","driver":{"shortName":"HAD","nationality":"France"}},
+{"driverId":"tsunoda","driver":{"shortName":"TSU","nationality":"Japan"}},
+{"driverId":"doohan","driver":{"shortName":"DOO","nationality":"Australia"}},
+{"driverId":"bottas","driver":{"shortName":"BOT","nationality":"Finland"}},
+{"driverId":"perez","driver":{"shortName":"PER","nationality":"Mexico"}},
+{"driverId":"lindblad","driver":{"shortName":"LIN","nationality":"Great Britain"}}
+]}`
+
+func TestGridNationalities(t *testing.T) {
+	var resp DriverChampionshipResponse
+	if err := json.Unmarshal([]byte(gridNationalitiesJSON), &resp); err != nil {
+		t.Fatalf("Fixture should decode: %v", err)
+	}
+	want := map[string]string{
+		"norris": "gb", "piastri": "au", "max_verstappen": "nl", "russell": "gb", "leclerc": "mc", "hamilton": "gb",
+		"antonelli": "it", "albon": "th", "sainz": "es", "alonso": "es", "stroll": "ca", "hulkenberg": "de",
+		"bortoleto": "br", "ocon": "fr", "bearman": "gb", "gasly": "fr", "colapinto": "ar", "lawson": "nz",
+		"hadjar": "fr", "tsunoda": "jp", "doohan": "au", "bottas": "fi", "perez": "mx", "lindblad": "gb",
+	}
+	for _, standing := range resp.DriversChampionship {
+		if code := driverCountryCode(standing.Driver); code != want[standing.DriverID] {
+			t.Errorf("%s: nationality %q resolved to %q, want %q", standing.DriverID, standing.Driver.Nationality, code, want[standing.DriverID])
+		}
+	}
+}
+
+func TestDemonymFlags(t *testing.T) {
+	driver := Driver{Nationality: "Dutch"}
+	if got := shortcodeFlags.flag(driverCountryCode(driver)); got != ":flag-nl:" {
+		t.Errorf("Expected :flag-nl: for a Dutch driver, got %q", got)
+	}
+	driver = Driver{Nationality: "British"}
+	if got := shortcodeFlags.flag(driverCountryCode(driver)); got != ":gb:" {
+		t.Errorf("Expected :gb: for a British driver, got %q", got)
+	}
+	driver = Driver{Nationality: "Monegasque"}
+	if got := unicodeFlags.flag(driverCountryCode(driver)); got != "🇲🇨" {
+		t.Errorf("Expected 🇲🇨 for a Monegasque driver, got %q", got)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// discordMessageLimit is the maximum length of a Discord message's content
+const discordMessageLimit = 2000
+
+// discordRenderer renders Discord-flavoured markdown with Unicode flags
+type discordRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r discordRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds a Discord message for the next race and championship standings
+func (r discordRenderer) Render(data *TopicData) string {
+	var sb strings.Builder
+	emoji := emojiSet{flags: r.flags.or(unicodeFlags)}
+
+	sb.WriteString(fmt.Sprintf("## 🏎️ F1 %d\n", data.Season))
+
+	if data.OffSeason {
+		sb.WriteString(fmt.Sprintf("**The %d season is over**\n", data.Season))
+		for _, fact := range offSeasonFacts(data, emoji) {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", fact.Label, fact.Value))
+		}
+	} else if data.NextRaceErr != nil {
+		sb.WriteString("**Next:** No upcoming races\n")
+	} else {
+		race := data.NextRace
+		sb.WriteString(fmt.Sprintf("**Next:** Round %d · %s %s\n",
+			data.Round, race.RaceName, emoji.raceFlag(race)))
+		sb.WriteString(fmt.Sprintf("%s, %s\n", race.Circuit.CircuitName, race.Country))
+		if qualy := discordSessionTime(race.Schedule.Qualy); qualy != "" {
+			sb.WriteString(fmt.Sprintf("Qualifying: %s\n", qualy))
+		}
+		if raceTime := discordSessionTime(race.Schedule.Race); raceTime != "" {
+			sb.WriteString(fmt.Sprintf("Race: %s\n", raceTime))
+		}
+	}
+
+	if data.DriversErr == nil && len(data.Drivers) > 0 {
+		sb.WriteString("### Drivers\n")
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			sb.WriteString(fmt.Sprintf("%d. %s **%s** %s %s — %.0f\n",
+				driver.Position,
+				emoji.driverFlag(driver.Driver),
+				driverAbbr(driver.Driver),
+				driver.Driver.Name,
+				driver.Driver.Surname,
+				driver.Points))
+		}
+	}
+
+	if data.TeamsErr == nil && len(data.Teams) > 0 {
+		sb.WriteString("### Constructors\n")
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+			sb.WriteString(fmt.Sprintf("%d. **%s** %s — %.0f\n",
+				team.Position,
+				teamAbbr(team),
+				team.Team.TeamName,
+				team.Points))
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// discordSessionTime formats a session time with Discord's timestamp markup so each reader sees their own timezone
+func discordSessionTime(info TimeInfo) string {
+	t, hasTime, err := parseSessionTime(info)
+	if err != nil {
+		return ""
+	}
+	if !hasTime {
+		return t.Format("Mon Jan 2")
+	}
+	return fmt.Sprintf("<t:%d:F> (<t:%d:R>)", t.Unix(), t.Unix())
+}
+
+// discordPublisher posts to a Discord webhook
+type discordPublisher struct {
+	renderer Renderer
+	url      string
+}
+
+// newDiscordPublisher creates a publisher for the webhook URL from opts or $DISCORD_WEBHOOK_URL.
+// Only markdown formats are accepted, as Discord would show any other as raw text
+func newDiscordPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	switch renderer.(type) {
+	case discordRenderer, markdownRenderer:
+	default:
+		return nil, errors.New("the discord target needs -format discord or markdown")
+	}
+
+	url := opts.WebhookURL
+	if url == "" {
+		url = os.Getenv("DISCORD_WEBHOOK_URL")
+	}
+	if url == "" {
+		return nil, errors.New("no webhook URL: set -webhook-url or DISCORD_WEBHOOK_URL")
+	}
+	return &discordPublisher{renderer: renderer, url: url}, nil
+}
+
+// Publish sends the rendered text as the webhook message content
+func (p *discordPublisher) Publish(data *TopicData) error {
+	content, err := renderText(p.renderer, data)
+	if err != nil {
+		return err
+	}
+	if len(content) > discordMessageLimit {
+		return fmt.Errorf("Discord message exceeds %d character limit (%d characters)", discordMessageLimit, len(content))
+	}
+	return postWebhook(p.url, map[string]string{"content": content})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiscordRenderer(t *testing.T) {
+	output := discordRenderer{}.Render(testTopicData())
+
+	expected := []string{
+		"## 🏎️ F1 2025",
+		"**Next:** Round 3 · Lenovo Japanese Grand Prix 2025 🇯🇵",
+		"Race: <t:1743915600:F> (<t:1743915600:R>)",
+		"1. 🇬🇧 **NOR** Lando Norris — 44",
+		"2. 🇳🇱 **VER** Max Verstappen — 36",
+		"1. **MCL** McLaren Formula 1 Team — 78",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Slack shortcodes mean nothing in Discord
+	if strings.Contains(output, ":flag-") || strings.Contains(output, ":f1") {
+		t.Errorf("Output should not contain Slack shortcodes, got:\n%s", output)
+	}
+}
+
+func TestDiscordPublisher(t *testing.T) {
+	server, received := webhookRecorder(t, http.StatusNoContent)
+
+	publisher, err := newPublisher("discord", discordRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var payload map[string]string
+	if err := json.Unmarshal(*received, &payload); err != nil {
+		t.Fatalf("Webhook payload should be JSON: %v", err)
+	}
+	if !strings.HasPrefix(payload["content"], "## 🏎️ F1 2025") {
+		t.Errorf("Unexpected content: %q", payload["content"])
+	}
+}
+
+func TestDiscordPublisherFormats(t *testing.T) {
+	for _, format := range []string{"discord", "markdown"} {
+		if _, err := newPublisher("discord", renderers[format], PublishOptions{WebhookURL: "https://example.com/webhook"}); err != nil {
+			t.Errorf("Format %s should be accepted, got %v", format, err)
+		}
+	}
+	for _, format := range []string{"text", "slack", "html", "blocks", "teams"} {
+		if _, err := newPublisher("discord", renderers[format], PublishOptions{WebhookURL: "https://example.com/webhook"}); err == nil {
+			t.Errorf("Format %s should be rejected", format)
+		}
+	}
+}
+
+func TestDiscordPublisherTooLong(t *testing.T) {
+	server, _ := webhookRecorder(t, http.StatusNoContent)
+
+	data := testTopicData()
+	data.NextRace.RaceName = strings.Repeat("x", discordMessageLimit)
+
+	publisher, _ := newPublisher("discord", discordRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err := publisher.Publish(data); err == nil {
+		t.Error("Expected an error for a message over the Discord limit")
+	}
+}
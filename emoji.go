@@ -0,0 +1,280 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// flagStyle is how flags are rendered from 2-letter ISO 3166 country codes
+type flagStyle string
+
+const (
+	// shortcodeFlags renders Slack shortcodes such as :flag-jp:
+	shortcodeFlags flagStyle = "shortcode"
+	// unicodeFlags renders regional indicator pairs such as 🇯🇵
+	unicodeFlags flagStyle = "unicode"
+)
+
+// flagStyles lists the valid -emoji-style values
+var flagStyles = []flagStyle{shortcodeFlags, unicodeFlags}
+
+// flagStyleNames returns the sorted list of flag style names
+func flagStyleNames() []string {
+	names := make([]string, 0, len(flagStyles))
+	for _, style := range flagStyles {
+		names = append(names, string(style))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseFlagStyle validates a -emoji-style value
+func parseFlagStyle(s string) (flagStyle, error) {
+	for _, style := range flagStyles {
+		if string(style) == s {
+			return style, nil
+		}
+	}
+	return "", fmt.Errorf("unknown emoji style %q, expected one of: %s", s, strings.Join(flagStyleNames(), ", "))
+}
+
+// or returns s, or def if s is unset
+func (s flagStyle) or(def flagStyle) flagStyle {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// Map ISO codes to Slack shortcodes that don't follow the :flag-xx: pattern
+var slackFlagShortcodes = map[string]string{
+	"gb": ":gb:",
+}
+
+// flag renders the flag for a 2-letter ISO country code, or an unknown flag if the code is ""
+func (s flagStyle) flag(code string) string {
+	if s == unicodeFlags {
+		return unicodeFlag(code)
+	}
+	if code == "" {
+		return ":flag-xx:"
+	}
+	if shortcode, exists := slackFlagShortcodes[code]; exists {
+		return shortcode
+	}
+	return fmt.Sprintf(":flag-%s:", code)
+}
+
+// flagStyler is implemented by renderers whose flag style can be overridden with -emoji-style
+type flagStyler interface {
+	withFlags(style flagStyle) Renderer
+}
+
+// emojiSet holds the emoji a renderer uses, so each target only gets emoji it can display
+type emojiSet struct {
+	flags flagStyle
+	// custom is whether the target can show the Slack workspace's custom driver and team emoji
+	custom bool
+}
+
+// raceFlag returns the flag for a race's country
+func (e emojiSet) raceFlag(race *Race) string {
+	return e.flags.flag(raceCountryCode(race))
+}
+
+// driverFlag returns the flag for a driver's nationality
+func (e emojiSet) driverFlag(driver Driver) string {
+	return e.flags.flag(driverCountryCode(driver))
+}
+
+// f1 returns the workspace's F1 logo emoji
+func (e emojiSet) f1() string {
+	return withoutMissingEmoji(f1Emoji)
+}
+
+// driver returns the emoji for a driver ID, or a generic one if the driver isn't mapped
+func (e emojiSet) driver(driverID string) string {
+	if !e.custom {
+		return ""
+	}
+	if emoji, exists := lookupDriverEmoji(driverID); exists {
+		return withoutMissingEmoji(emoji)
+	}
+	warnOnce("no emoji for driver %q, add it to driverEmojis", driverID)
+	return fallbackDriverEmoji
+}
+
+// team returns the emoji for a team ID, or a generic one if the team isn't mapped
+func (e emojiSet) team(teamID string) string {
+	if !e.custom {
+		return ""
+	}
+	if emoji, exists := lookupTeamEmoji(teamID); exists {
+		return withoutMissingEmoji(emoji)
+	}
+	warnOnce("no emoji for team %q, add it to teamEmojis", teamID)
+	return fallbackTeamEmoji
+}
+
+// unicodeFlag converts a 2-letter country code into a flag made of regional indicator symbols,
+// or a white flag if the code isn't two letters
+func unicodeFlag(code string) string {
+	code = strings.ToUpper(code)
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return "🏳️"
+	}
+	const regionalIndicatorA = 0x1F1E6
+	return string([]rune{
+		rune(regionalIndicatorA + int(code[0]-'A')),
+		rune(regionalIndicatorA + int(code[1]-'A')),
+	})
+}
+
+// Standard Slack emoji used for teams and drivers without an entry in the maps
+const (
+	fallbackTeamEmoji   = ":racing_car:"
+	fallbackDriverEmoji = ":bust_in_silhouette:"
+)
+
+// lookupTeamEmoji returns the configured or built-in emoji for a team ID
+func lookupTeamEmoji(teamID string) (string, bool) {
+	if emoji, exists := config.TeamEmojis[teamID]; exists {
+		return emoji, true
+	}
+	info, exists := teamEmojis[teamID]
+	return info.emoji, exists && !info.noEmoji
+}
+
+// lookupTeamAbbr returns the configured or built-in abbreviation for a team ID
+func lookupTeamAbbr(teamID string) (string, bool) {
+	if abbr, exists := config.TeamAbbrs[teamID]; exists {
+		return abbr, true
+	}
+	info, exists := teamEmojis[teamID]
+	return info.abbr, exists
+}
+
+// lookupDriverEmoji returns the configured or built-in emoji for a driver ID
+func lookupDriverEmoji(driverID string) (string, bool) {
+	if emoji, exists := config.DriverEmojis[driverID]; exists {
+		return emoji, true
+	}
+	emoji, exists := driverEmojis[driverID]
+	return emoji, exists
+}
+
+// genericTeamWords are left out when deriving an abbreviation from a team name
+var genericTeamWords = map[string]bool{
+	"formula": true, "1": true, "one": true, "f1": true, "team": true, "racing": true, "scuderia": true,
+}
+
+// teamAbbr returns the abbreviation for a team, deriving one from the team name if it isn't mapped
+func teamAbbr(team TeamStanding) string {
+	if abbr, exists := lookupTeamAbbr(team.TeamID); exists {
+		return abbr
+	}
+	return deriveTeamAbbr(team.TeamID, team.Team.TeamName)
+}
+
+// deriveTeamAbbr derives a three-letter abbreviation from the first distinctive word of a team name,
+// e.g. "Cadillac Formula 1 Team" -> "CAD", falling back to the team ID
+func deriveTeamAbbr(teamID, teamName string) string {
+	for _, word := range strings.Fields(teamName) {
+		if !genericTeamWords[strings.ToLower(word)] {
+			return abbreviate(word)
+		}
+	}
+	return abbreviate(strings.ReplaceAll(teamID, "_", ""))
+}
+
+// driverAbbr returns the abbreviation for a driver, deriving one from their surname if the API has none
+func driverAbbr(driver Driver) string {
+	if driver.ShortName != "" {
+		return driver.ShortName
+	}
+	return abbreviate(driver.Surname)
+}
+
+// abbreviate returns the first three letters of a word in upper case
+func abbreviate(word string) string {
+	var letters []rune
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			letters = append(letters, unicode.ToUpper(r))
+		}
+		if len(letters) == 3 {
+			break
+		}
+	}
+	return string(letters)
+}
+
+// unmappedEntities lists every team and driver ID in the standings that lacks an emoji or abbreviation entry
+func unmappedEntities(drivers []DriverStanding, teams []TeamStanding) []string {
+	var missing []string
+
+	// Teams can appear in either championship, so collect them from both
+	teamNames := map[string]string{}
+	for _, team := range teams {
+		teamNames[team.TeamID] = team.Team.TeamName
+	}
+	for _, driver := range drivers {
+		if _, exists := teamNames[driver.TeamID]; !exists {
+			teamNames[driver.TeamID] = driver.Team.TeamName
+		}
+	}
+	teamIDs := make([]string, 0, len(teamNames))
+	for teamID := range teamNames {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Strings(teamIDs)
+
+	for _, teamID := range teamIDs {
+		var gaps []string
+		if _, exists := lookupTeamEmoji(teamID); !exists {
+			gaps = append(gaps, "emoji")
+		}
+		if _, exists := lookupTeamAbbr(teamID); !exists {
+			gaps = append(gaps, fmt.Sprintf("abbreviation (using %s)", deriveTeamAbbr(teamID, teamNames[teamID])))
+		}
+		if len(gaps) > 0 {
+			missing = append(missing, fmt.Sprintf("team %s (%s): no %s", teamID, teamNames[teamID], strings.Join(gaps, ", no ")))
+		}
+	}
+
+	for _, driver := range drivers {
+		if _, exists := lookupDriverEmoji(driver.DriverID); !exists {
+			missing = append(missing, fmt.Sprintf("driver %s (%s %s, %s): no emoji",
+				driver.DriverID, driver.Driver.Name, driver.Driver.Surname, driverAbbr(driver.Driver)))
+		}
+	}
+
+	return missing
+}
+
+// unmappedCommand reports every team and driver on the grid that lacks an entry, exiting non-zero if there are any
+func unmappedCommand(args []string) error {
+	fs := flag.NewFlagSet("unmapped", flag.ExitOnError)
+	fs.Parse(args)
+
+	data := fetchTopicData()
+	if data.DriversErr != nil {
+		return data.DriversErr
+	}
+	if data.TeamsErr != nil {
+		return data.TeamsErr
+	}
+
+	missing := unmappedEntities(data.Drivers, data.Teams)
+	if len(missing) == 0 {
+		fmt.Println("All teams and drivers are mapped")
+		return nil
+	}
+	for _, line := range missing {
+		fmt.Println(line)
+	}
+	return fmt.Errorf("%d unmapped entities, add them to the config", len(missing))
+}
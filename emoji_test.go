@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnicodeFlag(t *testing.T) {
+	tests := map[string]string{
+		"gb": "🇬🇧",
+		"NL": "🇳🇱",
+		"jp": "🇯🇵",
+		"":   "🏳️",
+		"x":  "🏳️",
+		"1a": "🏳️",
+	}
+	for code, want := range tests {
+		if got := unicodeFlag(code); got != want {
+			t.Errorf("unicodeFlag(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestParseFlagStyle(t *testing.T) {
+	if style, err := parseFlagStyle("unicode"); err != nil || style != unicodeFlags {
+		t.Errorf("parseFlagStyle(unicode) = %q, %v", style, err)
+	}
+	if _, err := parseFlagStyle("ascii"); err == nil {
+		t.Error("Expected an error for an unknown emoji style")
+	}
+}
+
+func TestDeriveTeamAbbr(t *testing.T) {
+	tests := []struct {
+		teamID, teamName, want string
+	}{
+		{"cadillac", "Cadillac Formula 1 Team", "CAD"},
+		{"audi", "Audi F1 Team", "AUD"},
+		{"aston_martin", "Aston Martin Aramco F1 Team", "AST"},
+		{"haas", "Haas F1 Team", "HAA"},
+		{"mystery", "Formula 1 Team", "MYS"},
+		{"x_y", "", "XY"},
+	}
+	for _, test := range tests {
+		if got := deriveTeamAbbr(test.teamID, test.teamName); got != test.want {
+			t.Errorf("deriveTeamAbbr(%q, %q) = %q, want %q", test.teamID, test.teamName, got, test.want)
+		}
+	}
+}
+
+func TestDriverAbbr(t *testing.T) {
+	if got := driverAbbr(Driver{ShortName: "NOR", Surname: "Norris"}); got != "NOR" {
+		t.Errorf("Expected the API short name, got %q", got)
+	}
+	if got := driverAbbr(Driver{Surname: "Lindblad"}); got != "LIN" {
+		t.Errorf("Expected an abbreviation derived from the surname, got %q", got)
+	}
+}
+
+func TestUnknownTeamAndDriverFallbacks(t *testing.T) {
+	data := testTopicData()
+	data.Drivers[2] = DriverStanding{DriverID: "lindblad", TeamID: "cadillac", Points: 35, Position: 3,
+		Driver: Driver{Name: "Arvid", Surname: "Lindblad", Nationality: "Great Britain"},
+		Team:   Team{TeamName: "Cadillac Formula 1 Team"}}
+	data.Teams[2] = TeamStanding{TeamID: "cadillac", Points: 36, Position: 3, Team: Team{TeamName: "Cadillac Formula 1 Team"}}
+
+	output := slackRenderer{}.Render(data)
+	if !strings.Contains(output, ":bust_in_silhouette:LIN :gb: (35)") {
+		t.Errorf("Expected fallback driver emoji and abbreviation, got %s", output)
+	}
+	if !strings.Contains(output, ":racing_car:CAD (36)") {
+		t.Errorf("Expected fallback team emoji and abbreviation, got %s", output)
+	}
+}
+
+func TestAlpineFallsBackToGenericEmoji(t *testing.T) {
+	alpine := TeamStanding{TeamID: "alpine", Team: Team{TeamName: "BWT Alpine F1 Team"}}
+	emoji := emojiSet{custom: true}
+	if got := emoji.team(alpine.TeamID) + teamAbbr(alpine); got != ":racing_car:ALP" {
+		t.Errorf("Expected Alpine to use the fallback emoji with its abbreviation, got %q", got)
+	}
+}
+
+func TestConfigEmojiOverrides(t *testing.T) {
+	oldConfig := config
+	t.Cleanup(func() { config = oldConfig })
+	config = Config{
+		TeamEmojis:   map[string]string{"cadillac": ":f1tc:"},
+		TeamAbbrs:    map[string]string{"cadillac": "CAD"},
+		DriverEmojis: map[string]string{"norris": ":f1ln1:"},
+	}
+
+	emoji := emojiSet{flags: shortcodeFlags, custom: true}
+	if got := emoji.team("cadillac"); got != ":f1tc:" {
+		t.Errorf("Expected configured team emoji, got %q", got)
+	}
+	if got := emoji.driver("norris"); got != ":f1ln1:" {
+		t.Errorf("Expected configured driver emoji to override the built-in one, got %q", got)
+	}
+	if got := emoji.team("ferrari"); got != ":f1tf:" {
+		t.Errorf("Expected built-in team emoji, got %q", got)
+	}
+}
+
+func TestUnmappedEntities(t *testing.T) {
+	data := testTopicData()
+	data.Drivers = append(data.Drivers, DriverStanding{DriverID: "bottas", TeamID: "cadillac",
+		Driver: Driver{Name: "Valtteri", Surname: "Bottas", ShortName: "BOT"},
+		Team:   Team{TeamName: "Cadillac Formula 1 Team"}})
+
+	missing := unmappedEntities(data.Drivers, data.Teams)
+	expected := []string{
+		"team cadillac (Cadillac Formula 1 Team): no emoji, no abbreviation (using CAD)",
+		"driver bottas (Valtteri Bottas, BOT): no emoji",
+	}
+	if len(missing) != len(expected) {
+		t.Fatalf("Expected %d unmapped entities, got %q", len(expected), missing)
+	}
+	for i := range expected {
+		if missing[i] != expected[i] {
+			t.Errorf("Unmapped entity %d = %q, want %q", i, missing[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// f1Emoji is the workspace's custom F1 logo emoji that starts the topic
+const f1Emoji = ":f1:"
+
+// Map custom emoji to the text used instead when they're missing from the workspace.
+// Anything not listed is dropped, since the abbreviation next to it already says the same thing.
+var emojiTextFallbacks = map[string]string{
+	f1Emoji: "F1",
+}
+
+// missingEmoji holds the custom emoji known to be missing from the workspace, set by -emoji-fallback
+var missingEmoji map[string]bool
+
+// shortcodePattern matches a single Slack emoji shortcode such as :f1tl:
+var shortcodePattern = regexp.MustCompile(`:[a-z0-9_+'-]+:`)
+
+// withoutMissingEmoji replaces each shortcode in s that's missing from the workspace with its text fallback
+func withoutMissingEmoji(s string) string {
+	if len(missingEmoji) == 0 {
+		return s
+	}
+	return shortcodePattern.ReplaceAllStringFunc(s, func(shortcode string) string {
+		if missingEmoji[shortcode] {
+			return emojiTextFallbacks[shortcode]
+		}
+		return shortcode
+	})
+}
+
+// customShortcodes returns every custom emoji shortcode in the built-in and configured maps, sorted
+func customShortcodes() []string {
+	values := []string{f1Emoji}
+	for _, info := range teamEmojis {
+		values = append(values, info.emoji)
+	}
+	for _, emoji := range driverEmojis {
+		values = append(values, emoji)
+	}
+	for _, emoji := range config.TeamEmojis {
+		values = append(values, emoji)
+	}
+	for _, emoji := range config.DriverEmojis {
+		values = append(values, emoji)
+	}
+
+	seen := map[string]bool{}
+	var shortcodes []string
+	for _, value := range values {
+		for _, shortcode := range shortcodePattern.FindAllString(value, -1) {
+			if !seen[shortcode] {
+				seen[shortcode] = true
+				shortcodes = append(shortcodes, shortcode)
+			}
+		}
+	}
+	sort.Strings(shortcodes)
+	return shortcodes
+}
+
+// ListEmoji returns the names of the workspace's custom emoji with emoji.list
+func (c *slackClient) ListEmoji() (map[string]bool, error) {
+	var resp struct {
+		Emoji map[string]string `json:"emoji"`
+	}
+	if err := c.callForm("emoji.list", url.Values{}, &resp); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(resp.Emoji))
+	for name := range resp.Emoji {
+		names[name] = true
+	}
+	return names, nil
+}
+
+// findMissingEmoji returns the custom shortcodes in the maps that the workspace doesn't have
+func findMissingEmoji(client *slackClient) ([]string, error) {
+	workspaceEmoji, err := client.ListEmoji()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, shortcode := range customShortcodes() {
+		if !workspaceEmoji[strings.Trim(shortcode, ":")] {
+			missing = append(missing, shortcode)
+		}
+	}
+	return missing, nil
+}
+
+// loadMissingEmoji checks the workspace's emoji so rendering can substitute text for any that are missing
+func loadMissingEmoji(token string) error {
+	tok, err := slackToken(token)
+	if err != nil {
+		return err
+	}
+	missing, err := findMissingEmoji(newSlackClient(tok))
+	if err != nil {
+		return err
+	}
+
+	missingEmoji = map[string]bool{}
+	for _, shortcode := range missing {
+		warnOnce("custom emoji %s is missing from the workspace, using a text fallback", shortcode)
+		missingEmoji[shortcode] = true
+	}
+	return nil
+}
+
+// checkEmojiCommand reports every custom emoji in the maps that's missing from the workspace
+func checkEmojiCommand(args []string) error {
+	fs := flag.NewFlagSet("check-emoji", flag.ExitOnError)
+	token := fs.String("token", "", "Slack token with the emoji:read scope (defaults to $SLACK_TOKEN)")
+	fs.Parse(args)
+
+	tok, err := slackToken(*token)
+	if err != nil {
+		return err
+	}
+	missing, err := findMissingEmoji(newSlackClient(tok))
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		fmt.Printf("All %d custom emoji exist in the workspace\n", len(customShortcodes()))
+		return nil
+	}
+	for _, shortcode := range missing {
+		fmt.Println(shortcode)
+	}
+	return fmt.Errorf("%d custom emoji missing from the workspace", len(missing))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// workspaceEmojiJSON is an emoji.list response with every custom emoji except :f1: and :f1mv:
+func workspaceEmojiJSON() string {
+	var entries []string
+	for _, shortcode := range customShortcodes() {
+		name := strings.Trim(shortcode, ":")
+		if name == "f1" || name == "f1mv" {
+			continue
+		}
+		entries = append(entries, `"`+name+`":"https://emoji.slack-edge.com/T000/`+name+`.png"`)
+	}
+	return `{"ok":true,"emoji":{` + strings.Join(entries, ",") + `}}`
+}
+
+func TestCustomShortcodes(t *testing.T) {
+	shortcodes := customShortcodes()
+
+	for _, want := range []string{":f1:", ":m1:", ":f1tl:", ":f1mv:"} {
+		found := false
+		for _, shortcode := range shortcodes {
+			if shortcode == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("customShortcodes should contain %s, got %v", want, shortcodes)
+		}
+	}
+	for _, shortcode := range shortcodes {
+		if strings.Count(shortcode, ":") != 2 {
+			t.Errorf("Combined emoji should be split into single shortcodes, got %q", shortcode)
+		}
+	}
+}
+
+func TestFindMissingEmoji(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.responses["emoji.list"] = workspaceEmojiJSON()
+
+	missing, err := findMissingEmoji(newSlackClient("xoxb-test"))
+	if err != nil {
+		t.Fatalf("findMissingEmoji failed: %v", err)
+	}
+	if strings.Join(missing, " ") != ":f1: :f1mv:" {
+		t.Errorf("Expected :f1: and :f1mv: to be missing, got %v", missing)
+	}
+	// emoji.list is a read method, called with form arguments rather than a JSON body
+	if body := string(fake.requests["emoji.list"]); body != "" {
+		t.Errorf("emoji.list should be called without a JSON body, got %q", body)
+	}
+}
+
+func TestMissingEmojiFallback(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.responses["emoji.list"] = workspaceEmojiJSON()
+	t.Cleanup(func() { missingEmoji = nil })
+
+	if err := loadMissingEmoji("xoxb-test"); err != nil {
+		t.Fatalf("loadMissingEmoji failed: %v", err)
+	}
+
+	output := slackRenderer{}.Render(testTopicData())
+	if !strings.HasPrefix(output, "F1 2025 Next:") {
+		t.Errorf("Missing :f1: should fall back to text, got %s", output)
+	}
+	if !strings.Contains(output, ", VER :flag-nl: (36)") {
+		t.Errorf("Missing :f1mv: should be dropped, got %s", output)
+	}
+	if !strings.Contains(output, ":m1::f1tl:MCL") {
+		t.Errorf("Emoji that exist should be kept, got %s", output)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	// Embed the timezone database so users' timezones resolve in minimal containers
+	_ "time/tzdata"
+)
+
+// UserTimezone returns a user's timezone with users.info, UTC if they haven't set one
+func (c *slackClient) UserTimezone(userID string) (*time.Location, error) {
+	var resp struct {
+		User struct {
+			TZ string `json:"tz"`
+		} `json:"user"`
+	}
+	if err := c.callForm("users.info", url.Values{"user": {userID}}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.User.TZ == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(resp.User.TZ)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q for %s: %v", resp.User.TZ, userID, err)
+	}
+	return loc, nil
+}
+
+// slackEventEnvelope is the outer payload of an Events API request
+type slackEventEnvelope struct {
+	Type      string     `json:"type"`
+	Challenge string     `json:"challenge"`
+	Event     slackEvent `json:"event"`
+}
+
+// slackEvent is the event inside an event_callback, only the app_mention fields are kept
+type slackEvent struct {
+	Type     string `json:"type"`
+	User     string `json:"user"`
+	BotID    string `json:"bot_id"`
+	Text     string `json:"text"`
+	Channel  string `json:"channel"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// slackMentionPattern matches user mentions such as <@U0123ABC>
+var slackMentionPattern = regexp.MustCompile(`<@[A-Z0-9]+(\|[^>]*)?>`)
+
+// sessionKeywords map words in a question to the session they ask about, checked in order
+var sessionKeywords = []struct {
+	words []string
+	key   string
+}{
+	{[]string{"sprint quali", "sprint qualy", "sprint qualifying", "sprint shootout", "shootout"}, "sprint-qualy"},
+	{[]string{"sprint"}, "sprint"},
+	{[]string{"quali", "qualy", "qualifying", "pole"}, "qualy"},
+	{[]string{"fp1", "practice 1"}, "fp1"},
+	{[]string{"fp2", "practice 2"}, "fp2"},
+	{[]string{"fp3", "practice 3"}, "fp3"},
+	{[]string{"practice", "fp"}, "fp"},
+	{[]string{"race", "gp", "grand prix", "lights out"}, "race"},
+}
+
+// questionWords splits a question into its words, without surrounding punctuation or a possessive 's
+func questionWords(question string) []string {
+	var words []string
+	for _, word := range strings.Fields(question) {
+		if word = strings.TrimSuffix(strings.Trim(word, "?!.,"), "'s"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// containsPhrase reports whether the words include all of a phrase's words in a row, so "race" isn't
+// found in "embrace" or "racing"
+func containsPhrase(words []string, phrase string) bool {
+	want := strings.Fields(phrase)
+	for i := 0; i+len(want) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
+
+// askedSession returns the key of the session a question asks about, or "" for the next session of any kind
+func askedSession(question string) string {
+	words := questionWords(question)
+	for _, keyword := range sessionKeywords {
+		for _, phrase := range keyword.words {
+			if containsPhrase(words, phrase) {
+				return keyword.key
+			}
+		}
+	}
+	return ""
+}
+
+// nextSession returns the first session of the race that hasn't started yet matching key,
+// where "fp" matches any practice session and "" any session
+func nextSession(race *Race, key string) (session, time.Time, bool) {
+	for _, s := range raceSessions(race) {
+		if key != "" && s.key != key && !(key == "fp" && strings.HasPrefix(s.key, "fp")) {
+			continue
+		}
+		start, _, _ := parseSessionTime(s.info)
+		if !start.Before(now()) {
+			return s, start, true
+		}
+	}
+	return session{}, time.Time{}, false
+}
+
+// untilSession describes how long until a session starts, e.g. "5 hours"
+func untilSession(start time.Time) string {
+	switch d := start.Sub(now()); {
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 48*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	default:
+		return plural(int(d.Hours()/24), "day")
+	}
+}
+
+// mentionAnswer answers a question the bot was mentioned in, giving session times in the asker's timezone
+func mentionAnswer(data *TopicData, text string, loc *time.Location) string {
+	emoji := emojiSet{flags: shortcodeFlags, custom: true}
+	question := strings.ToLower(strings.TrimSpace(slackMentionPattern.ReplaceAllString(text, "")))
+	words := questionWords(question)
+
+	if data.OffSeason {
+		return slackRenderer{}.Render(data)
+	}
+	for _, word := range []string{"standings", "championship", "points", "leader"} {
+		if containsPhrase(words, word) {
+			return standingsText(data, emoji)
+		}
+	}
+
+	var sb strings.Builder
+	if data.NextRaceErr != nil || data.NextRace == nil {
+		sb.WriteString("No upcoming races")
+	} else if s, start, ok := nextSession(data.NextRace, askedSession(question)); !ok {
+		sb.WriteString(fmt.Sprintf("No more sessions like that this weekend, see `/f1 next` for the %s", data.NextRace.RaceName))
+	} else {
+		when := start.In(loc).Format("Mon Jan 2")
+		if _, hasTime, _ := parseSessionTime(s.info); hasTime {
+			when = start.In(loc).Format("Mon Jan 2 at 15:04 MST") + " (in " + untilSession(start) + ")"
+		}
+		sb.WriteString(fmt.Sprintf("*%s* for the %s %s is %s", s.name, data.NextRace.RaceName, emoji.raceFlag(data.NextRace), when))
+	}
+
+	// Follow with the standings of any driver asked about, or the top 3
+	for _, word := range words {
+		// Only whole names count, as partial matches would pick drivers out of words like "and"
+		matches := exactMatch(data.Drivers, driverNames, word)
+		if len(matches) == 1 && data.DriversErr == nil {
+			return sb.String() + "\n" + driverText(data, matches[0].DriverID, emoji)
+		}
+	}
+	if data.DriversErr == nil && len(data.Drivers) > 0 {
+		var top []string
+		for i := 0; i < 3 && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			top = append(top, fmt.Sprintf("%d. %s%s (%.0f)", driver.Position, emoji.driver(driver.DriverID), driverAbbr(driver.Driver), driver.Points))
+		}
+		sb.WriteString("\nStandings: " + strings.Join(top, ", "))
+	}
+	return sb.String()
+}
+
+// slackEvents handles Events API requests, answering app_mention events in a thread
+func (s *topicServer) slackEvents(w http.ResponseWriter, r *http.Request) {
+	body, ok := readSignedBody(w, r, s.signingSecret)
+	if !ok {
+		return
+	}
+
+	var envelope slackEventEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	switch envelope.Type {
+	case "url_verification":
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, envelope.Challenge)
+		return
+	case "event_callback":
+		event := envelope.Event
+		// Slack retries events it didn't get a quick enough reply to, which have already been answered
+		if event.Type == "app_mention" && event.BotID == "" && r.Header.Get("X-Slack-Retry-Num") == "" {
+			// Slack wants a reply within 3 seconds, so answer after acknowledging
+			s.spawn(func() { s.answerMention(event) })
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// answerMention replies to a mention in its thread
+func (s *topicServer) answerMention(event slackEvent) {
+	text := "F1 data isn't available yet, try again in a minute"
+	if data, _ := s.cached("/slack/events"); data != nil {
+		loc, err := s.slack.UserTimezone(event.User)
+		if err != nil {
+			log.Printf("Error getting timezone for %s, using UTC: %v", event.User, err)
+			loc = time.UTC
+		}
+		text = mentionAnswer(data, event.Text, loc)
+	}
+
+	thread := event.ThreadTS
+	if thread == "" {
+		thread = event.TS
+	}
+	if err := s.slack.PostMessage(event.Channel, slackMessage{ThreadTS: thread, Text: text}); err != nil {
+		log.Printf("Error answering mention in %s: %v", event.Channel, err)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newEventsServer starts a topic server with the Events API enabled against a fake Slack
+func newEventsServer(t *testing.T) (*httptest.Server, *fakeSlack) {
+	t.Helper()
+	fake := newFakeSlack(t)
+	fake.responses["users.info"] = `{"ok": true, "user": {"id": "U123", "tz": "Asia/Tokyo"}}`
+
+	server := newTopicServer(testTopicData)
+	server.signingSecret = "test-secret"
+	server.slack = newSlackClient("xoxb-test")
+	server.spawn = func(f func()) { f() }
+	server.refresh()
+
+	httpServer := httptest.NewServer(server.handler())
+	t.Cleanup(httpServer.Close)
+	return httpServer, fake
+}
+
+// postEvent posts a signed Events API payload, returning the status and body
+func postEvent(t *testing.T, server *httptest.Server, payload string, header http.Header) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/slack/events", strings.NewReader(payload))
+	for key, values := range signSlackRequest("test-secret", payload) {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// mentionPayload is an app_mention event as Slack sends it
+const mentionPayload = `{"token": "x", "team_id": "T1", "type": "event_callback", "event_id": "Ev1", "event_time": 1743660000,
+	"event": {"type": "app_mention", "user": "U123", "text": "<@UBOT> when's quali?", "ts": "1743660000.000100", "channel": "C123"}}`
+
+func TestSlackEventsURLVerification(t *testing.T) {
+	httpServer, _ := newEventsServer(t)
+
+	status, body := postEvent(t, httpServer, `{"token": "x", "challenge": "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P", "type": "url_verification"}`, nil)
+	if status != http.StatusOK || body != "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P" {
+		t.Errorf("Expected the challenge back, got %d %q", status, body)
+	}
+}
+
+func TestSlackEventsAppMention(t *testing.T) {
+	fixClock(t, time.Date(2025, 4, 3, 6, 0, 0, 0, time.UTC))
+	httpServer, fake := newEventsServer(t)
+
+	if status, _ := postEvent(t, httpServer, mentionPayload, nil); status != http.StatusOK {
+		t.Fatalf("Expected the event to be acknowledged, got %d", status)
+	}
+
+	if got := string(fake.requests["users.info"]); got != "user=U123" {
+		t.Errorf("users.info should be called with the asker, got %q", got)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &msg); err != nil {
+		t.Fatalf("chat.postMessage body should be JSON: %v", err)
+	}
+	expected := "*Qualifying* for the Lenovo Japanese Grand Prix 2025 :flag-jp: is Sat Apr 5 at 15:00 JST (in 2 days)\n" +
+		"Standings: 1. :f1ln:NOR (44), 2. :f1mv:VER (36), 3. :f1gr:RUS (35)"
+	if msg.Channel != "C123" || msg.ThreadTS != "1743660000.000100" || msg.Text != expected {
+		t.Errorf("Unexpected reply:\n got: %+v\nwant text: %s", msg, expected)
+	}
+}
+
+func TestSlackEventsIgnoresRetries(t *testing.T) {
+	httpServer, fake := newEventsServer(t)
+
+	header := http.Header{"X-Slack-Retry-Num": {"1"}}
+	if status, _ := postEvent(t, httpServer, mentionPayload, header); status != http.StatusOK {
+		t.Errorf("Retries should still be acknowledged, got %d", status)
+	}
+	if _, posted := fake.requests["chat.postMessage"]; posted {
+		t.Error("A retried mention shouldn't be answered twice")
+	}
+}
+
+func TestSlackEventsRejectsUnsigned(t *testing.T) {
+	httpServer, _ := newEventsServer(t)
+
+	resp, err := http.Post(httpServer.URL+"/slack/events", "application/json", strings.NewReader(mentionPayload))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("An unsigned event should be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestMentionAnswer(t *testing.T) {
+	fixClock(t, time.Date(2025, 4, 5, 7, 0, 0, 0, time.UTC))
+	london, _ := time.LoadLocation("Europe/London")
+
+	tests := []struct {
+		question string
+		want     string
+	}{
+		// Qualifying has started, so the next session is the race
+		{"<@UBOT> what's next?", "*Race* for the Lenovo Japanese Grand Prix 2025 :flag-jp: is Sun Apr 6 at 06:00 BST (in 22 hours)"},
+		{"<@UBOT> when's quali?", "No more sessions like that this weekend"},
+		{"<@UBOT> how's Norris doing in the race?", "is Sun Apr 6 at 06:00 BST (in 22 hours)\n:f1ln:NOR *Lando Norris*"},
+		{"<@UBOT> standings please", "*2025 Drivers' Championship*\n1. :f1ln:NOR"},
+	}
+	for _, test := range tests {
+		if got := mentionAnswer(testTopicData(), test.question, london); !strings.Contains(got, test.want) {
+			t.Errorf("Answer to %q should contain %q, got:\n%s", test.question, test.want, got)
+		}
+	}
+}
+
+func TestAskedSession(t *testing.T) {
+	tests := map[string]string{
+		"when's quali?":            "qualy",
+		"what time is the sprint?": "sprint",
+		"sprint quali when":        "sprint-qualy",
+		"fp2 start?":               "fp2",
+		"when's practice":          "fp",
+		"lights out?":              "race",
+		"what's happening":         "",
+		"qualifying when?":         "qualy",
+		"sprint qualifying?":       "sprint-qualy",
+		"practice 1?":              "fp1",
+		"when's the gp?":           "race",
+
+		// Keywords inside other words don't count
+		"embrace the chaos":    "",
+		"who's racing?":        "",
+		"any fpv footage?":     "",
+		"good gps near suzuka": "",
+		"are the poles fast":   "",
+		"hired a sprinter van": "",
+	}
+	for question, want := range tests {
+		if got := askedSession(question); got != want {
+			t.Errorf("askedSession(%q) = %q, want %q", question, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+)
+
+// defaultFantasyCode is the league code shown when the config doesn't set one
+const defaultFantasyCode = "thanksai"
+
+// fantasyLeaderboardLimit is how many fantasy entries are kept from the leaderboard
+const fantasyLeaderboardLimit = 3
+
+// FantasyConfig configures the fantasy league segment at the end of the Slack topic
+type FantasyConfig struct {
+	// Disabled drops the segment entirely
+	Disabled bool `json:"disabled,omitempty"`
+	// Code is the league code people use to join, defaulting to "thanksai"
+	Code string `json:"code,omitempty"`
+	// JoinURL is an optional link to join the league
+	JoinURL string `json:"joinUrl,omitempty"`
+	// LeaderboardURL is an optional JSON endpoint for the league standings, see FantasyLeaderboardResponse
+	LeaderboardURL string `json:"leaderboardUrl,omitempty"`
+}
+
+// code returns the configured league code or the default
+func (c FantasyConfig) code() string {
+	if c.Code == "" {
+		return defaultFantasyCode
+	}
+	return c.Code
+}
+
+// FantasyEntry is a single team in the fantasy league standings
+type FantasyEntry struct {
+	Position int     `json:"position"`
+	Name     string  `json:"name"`
+	Points   float64 `json:"points"`
+}
+
+// FantasyLeaderboardResponse is the shape expected from the fantasy leaderboard endpoint
+type FantasyLeaderboardResponse struct {
+	Standings []FantasyEntry `json:"standings"`
+}
+
+// fetchFantasyLeaderboard gets the top of the fantasy league standings from a JSON endpoint
+func fetchFantasyLeaderboard(url string) ([]FantasyEntry, error) {
+	log.Printf("Fetching fantasy leaderboard from: %s", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching fantasy leaderboard: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fantasy leaderboard returned %s", resp.Status)
+	}
+
+	var leaderboard FantasyLeaderboardResponse
+	if err := json.Unmarshal(body, &leaderboard); err != nil {
+		return nil, fmt.Errorf("error unmarshaling fantasy leaderboard: %v", err)
+	}
+
+	// Don't trust the endpoint's order, rank by points and fill in missing positions
+	entries := leaderboard.Standings
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Points > entries[j].Points })
+	entries = entries[:min(len(entries), fantasyLeaderboardLimit)]
+	for i := range entries {
+		if entries[i].Position == 0 {
+			entries[i].Position = i + 1
+		}
+	}
+	return entries, nil
+}
+
+// fantasySegment returns the fantasy part of the Slack topic, or "" when it's disabled
+func fantasySegment(data *TopicData) string {
+	fantasy := config.Fantasy
+	if fantasy.Disabled {
+		return ""
+	}
+
+	segment := fmt.Sprintf(" // Fantasy: `%s`", fantasy.code())
+	if fantasy.JoinURL != "" {
+		segment += " " + fantasy.JoinURL
+	}
+	if data.FantasyErr == nil && len(data.Fantasy) > 0 {
+		leader := data.Fantasy[0]
+		segment += fmt.Sprintf(" (Leader: %s %.0f)", leader.Name, leader.Points)
+	}
+	return segment
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fantasyStub starts an httptest server that serves a fantasy leaderboard
+func fantasyStub(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchFantasyLeaderboard(t *testing.T) {
+	server := fantasyStub(t, http.StatusOK, `{"standings": [
+		{"name": "Box Box Box", "points": 812},
+		{"name": "DRS Train", "points": 940},
+		{"name": "Undercut", "points": 755},
+		{"name": "Backmarkers", "points": 301}
+	]}`)
+
+	entries, err := fetchFantasyLeaderboard(server.URL)
+	if err != nil {
+		t.Fatalf("fetchFantasyLeaderboard failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected the top 3 entries, got %+v", entries)
+	}
+	if entries[0].Name != "DRS Train" || entries[0].Position != 1 || entries[2].Name != "Undercut" {
+		t.Errorf("Entries should be ranked by points, got %+v", entries)
+	}
+}
+
+func TestFetchFantasyLeaderboardErrors(t *testing.T) {
+	for _, server := range []*httptest.Server{
+		fantasyStub(t, http.StatusInternalServerError, `oops`),
+		fantasyStub(t, http.StatusOK, `not json`),
+	} {
+		if _, err := fetchFantasyLeaderboard(server.URL); err == nil {
+			t.Errorf("Expected an error from %s", server.URL)
+		}
+	}
+}
+
+func TestFantasySegment(t *testing.T) {
+	defer func() { config = Config{} }()
+
+	withLeader := testTopicData()
+	withLeader.Fantasy = []FantasyEntry{{Position: 1, Name: "DRS Train", Points: 940}}
+	withError := testTopicData()
+	withError.Fantasy = withLeader.Fantasy
+	withError.FantasyErr = errors.New("unreachable")
+
+	tests := []struct {
+		fantasy FantasyConfig
+		data    *TopicData
+		want    string
+	}{
+		{FantasyConfig{}, testTopicData(), " // Fantasy: `thanksai`"},
+		{FantasyConfig{Code: "pitwall"}, testTopicData(), " // Fantasy: `pitwall`"},
+		{FantasyConfig{Code: "pitwall", JoinURL: "https://example.com/join"}, testTopicData(), " // Fantasy: `pitwall` https://example.com/join"},
+		{FantasyConfig{}, withLeader, " // Fantasy: `thanksai` (Leader: DRS Train 940)"},
+		{FantasyConfig{}, withError, " // Fantasy: `thanksai`"},
+		{FantasyConfig{Disabled: true}, withLeader, ""},
+	}
+	for _, test := range tests {
+		config.Fantasy = test.fantasy
+		if got := fantasySegment(test.data); got != test.want {
+			t.Errorf("fantasySegment with %+v = %q, want %q", test.fantasy, got, test.want)
+		}
+	}
+}
+
+func TestSlackRendererFantasyDisabled(t *testing.T) {
+	defer func() { config = Config{} }()
+	config.Fantasy.Disabled = true
+
+	output := slackRenderer{}.Render(testTopicData())
+	if strings.Contains(output, "Fantasy") || !strings.HasSuffix(output, ":f1tr:RBR (36)") {
+		t.Errorf("Topic should end with the standings, got %q", output)
+	}
+}
+
+func TestTextRendererFantasy(t *testing.T) {
+	data := testTopicData()
+	data.Fantasy = []FantasyEntry{{Position: 1, Name: "DRS Train", Points: 940}, {Position: 2, Name: "Box Box Box", Points: 812}}
+
+	output := textRenderer{}.Render(data)
+	if !strings.Contains(output, "Fantasy League (thanksai):\n1. DRS Train - 940 points\n2. Box Box Box - 812 points") {
+		t.Errorf("Text output should contain the fantasy standings, got:\n%s", output)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// headToHead compares a team's two drivers, the better placed first. Qualifying and Race count the rounds
+// each finished ahead of the other
+type headToHead struct {
+	TeamID     string
+	Team       Team
+	Drivers    [2]DriverStanding
+	Qualifying [2]int
+	Race       [2]int
+}
+
+// gap returns the points between the two drivers
+func (h headToHead) gap() float64 {
+	return math.Abs(h.Drivers[0].Points - h.Drivers[1].Points)
+}
+
+// teammatePairs groups the driver standings by team into head-to-heads, in the order of each team's best
+// placed driver. Teams that changed drivers mid-season compare the two with the most points
+func teammatePairs(drivers []DriverStanding) []headToHead {
+	var pairs []headToHead
+	seen := map[string]int{}
+	for _, driver := range drivers {
+		count := seen[driver.TeamID]
+		seen[driver.TeamID]++
+		switch count {
+		case 0:
+			pairs = append(pairs, headToHead{TeamID: driver.TeamID, Team: driver.Team, Drivers: [2]DriverStanding{driver}})
+		case 1:
+			for i := range pairs {
+				if pairs[i].TeamID == driver.TeamID {
+					pairs[i].Drivers[1] = driver
+				}
+			}
+		}
+	}
+
+	// Drop teams with a single driver in the standings
+	var complete []headToHead
+	for _, pair := range pairs {
+		if pair.Drivers[1].DriverID != "" {
+			complete = append(complete, pair)
+		}
+	}
+	return complete
+}
+
+// addRound counts who finished ahead in a round's qualifying and race, where both drivers took part.
+// A retirement counts as finishing behind a classified teammate
+func (h *headToHead) addRound(qualifying []QualifyingResult, race []RaceResult) {
+	grid := map[string]int{}
+	for _, result := range qualifying {
+		grid[result.Driver.DriverID] = int(result.GridPosition)
+	}
+	if ahead, ok := compareRound(grid[h.Drivers[0].DriverID], grid[h.Drivers[1].DriverID]); ok {
+		h.Qualifying[ahead]++
+	}
+
+	finish := map[string]int{}
+	for _, result := range race {
+		if didNotFinish(result) {
+			finish[result.Driver.DriverID] = math.MaxInt
+		} else {
+			finish[result.Driver.DriverID] = int(result.Position)
+		}
+	}
+	if ahead, ok := compareRound(finish[h.Drivers[0].DriverID], finish[h.Drivers[1].DriverID]); ok {
+		h.Race[ahead]++
+	}
+}
+
+// compareRound returns which of two positions is ahead, or false if either driver didn't take part
+// or neither was classified
+func compareRound(first, second int) (int, bool) {
+	if first == 0 || second == 0 || first == second {
+		return 0, false
+	}
+	if first < second {
+		return 0, true
+	}
+	return 1, true
+}
+
+// addRoundResults fetches the qualifying and race results of every round run so far and counts them
+// into the head-to-heads, skipping rounds without results
+func addRoundResults(data *TopicData, pairs []headToHead) {
+	for round := 1; round <= racesRun(data); round++ {
+		qualifying, err := fetchQualifyingResults(data.Season, round)
+		if err != nil {
+			log.Printf("Skipping round %d qualifying: %v", round, err)
+		}
+		var race []RaceResult
+		if results, err := fetchRaceResults(data.Season, round); err != nil {
+			log.Printf("Skipping round %d race: %v", round, err)
+		} else {
+			race = results.Results
+		}
+
+		for i := range pairs {
+			pairs[i].addRound(qualifying, race)
+		}
+	}
+}
+
+// closestBattle returns the head-to-head with the smallest points gap between teammates who have scored
+func closestBattle(pairs []headToHead) (headToHead, bool) {
+	var closest headToHead
+	found := false
+	for _, pair := range pairs {
+		if pair.Drivers[0].Points+pair.Drivers[1].Points == 0 {
+			continue
+		}
+		if !found || pair.gap() < closest.gap() {
+			closest, found = pair, true
+		}
+	}
+	return closest, found
+}
+
+// closestBattleSegment returns the closest teammate battle for the Slack topic if configured,
+// e.g. " // H2H: :f1ln:NOR 44-43 :f1op:PIA"
+func closestBattleSegment(data *TopicData, emoji emojiSet) string {
+	if !config.ShowClosestBattle || data.DriversErr != nil {
+		return ""
+	}
+	battle, ok := closestBattle(teammatePairs(data.Drivers))
+	if !ok {
+		return ""
+	}
+	first, second := battle.Drivers[0], battle.Drivers[1]
+	return fmt.Sprintf(" // H2H: %s%s %.0f-%.0f %s%s",
+		emoji.driver(first.DriverID), driverAbbr(first.Driver), first.Points,
+		second.Points, emoji.driver(second.DriverID), driverAbbr(second.Driver))
+}
+
+// headToHeadTable formats the head-to-heads as a table, with the qualifying and race columns only
+// when round results were counted
+func headToHeadTable(pairs []headToHead, withResults bool) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	header := "Team\tDrivers\tPoints\tPositions"
+	if withResults {
+		header += "\tQualifying\tRace"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, pair := range pairs {
+		first, second := pair.Drivers[0], pair.Drivers[1]
+		row := fmt.Sprintf("%s\t%s vs %s\t%g-%g\tP%d-P%d",
+			pair.Team.TeamName, driverAbbr(first.Driver), driverAbbr(second.Driver),
+			first.Points, second.Points, first.Position, second.Position)
+		if withResults {
+			row += fmt.Sprintf("\t%d-%d\t%d-%d", pair.Qualifying[0], pair.Qualifying[1], pair.Race[0], pair.Race[1])
+		}
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// h2hCommand prints the teammate head-to-heads for the season
+func h2hCommand(args []string) error {
+	fs := flag.NewFlagSet("h2h", flag.ExitOnError)
+	withResults := fs.Bool("results", true, "Count qualifying and race head-to-heads from each round's results (two requests per round)")
+	fs.Parse(args)
+
+	data := fetchTopicData()
+	if data.DriversErr != nil {
+		return data.DriversErr
+	}
+
+	pairs := teammatePairs(data.Drivers)
+	if len(pairs) == 0 {
+		return fmt.Errorf("no teams with two drivers in the standings")
+	}
+	if *withResults {
+		addRoundResults(data, pairs)
+	}
+
+	fmt.Print(headToHeadTable(pairs, *withResults))
+	if battle, ok := closestBattle(pairs); ok {
+		fmt.Printf("\nClosest battle: %s %s vs %s %s, %g points apart\n",
+			battle.Drivers[0].Driver.Name, battle.Drivers[0].Driver.Surname,
+			battle.Drivers[1].Driver.Name, battle.Drivers[1].Driver.Surname, battle.gap())
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// qualyJSON returns a qualifying result entry for the fake API
+func qualyJSON(position int, driverID string) string {
+	return fmt.Sprintf(`{"gridPosition": %d, "driver": {"driverId": %q}}`, position, driverID)
+}
+
+func TestTeammatePairs(t *testing.T) {
+	pairs := teammatePairs(profileData().Drivers)
+
+	// Only McLaren has both drivers in the fixture
+	if len(pairs) != 1 || pairs[0].TeamID != "mclaren" {
+		t.Fatalf("Expected the McLaren pair, got %+v", pairs)
+	}
+	if pairs[0].Drivers[0].DriverID != "norris" || pairs[0].Drivers[1].DriverID != "piastri" {
+		t.Errorf("Expected the better placed driver first, got %s and %s", pairs[0].Drivers[0].DriverID, pairs[0].Drivers[1].DriverID)
+	}
+}
+
+func TestHeadToHeadAddRound(t *testing.T) {
+	pair := teammatePairs(profileData().Drivers)[0]
+
+	// Piastri out-qualifies Norris but retires
+	pair.addRound(
+		[]QualifyingResult{{GridPosition: 1, Driver: ResultDriver{DriverID: "piastri"}}, {GridPosition: 2, Driver: ResultDriver{DriverID: "norris"}}},
+		[]RaceResult{{Position: 5, Driver: ResultDriver{DriverID: "norris"}}, {Position: 18, Retired: "Engine", Driver: ResultDriver{DriverID: "piastri"}}},
+	)
+	// Norris misses qualifying, and both retire from the race
+	pair.addRound(
+		[]QualifyingResult{{GridPosition: 3, Driver: ResultDriver{DriverID: "piastri"}}},
+		[]RaceResult{{Retired: "Collision", Driver: ResultDriver{DriverID: "norris"}}, {Retired: "Collision", Driver: ResultDriver{DriverID: "piastri"}}},
+	)
+
+	if pair.Qualifying != [2]int{0, 1} || pair.Race != [2]int{1, 0} {
+		t.Errorf("Expected qualifying 0-1 and race 1-0, got %v and %v", pair.Qualifying, pair.Race)
+	}
+}
+
+func TestClosestBattle(t *testing.T) {
+	data := profileData()
+	leclerc := DriverStanding{DriverID: "leclerc", TeamID: "ferrari", Points: 12, Position: 8,
+		Driver: Driver{Name: "Charles", Surname: "Leclerc", ShortName: "LEC"}}
+	data.Drivers = append(data.Drivers[:4], leclerc, data.Drivers[4]) // ahead of Hamilton
+	data.Drivers = append(data.Drivers,
+		DriverStanding{DriverID: "stroll", TeamID: "aston_martin", Points: 0, Position: 19},
+		DriverStanding{DriverID: "alonso", TeamID: "aston_martin", Points: 0, Position: 20},
+	)
+
+	battle, ok := closestBattle(teammatePairs(data.Drivers))
+	if !ok || battle.TeamID != "ferrari" || battle.gap() != 3 {
+		t.Errorf("Expected Ferrari 3 points apart, skipping teammates without points, got %s %g", battle.TeamID, battle.gap())
+	}
+
+	if output := (slackRenderer{}).Render(data); strings.Contains(output, "H2H") {
+		t.Errorf("Topic shouldn't show the closest battle unless configured, got %s", output)
+	}
+	defer func() { config = Config{} }()
+	config.ShowClosestBattle = true
+	if output := (slackRenderer{}).Render(data); !strings.Contains(output, " // H2H: :f1cl:LEC 12-9 :f1lh:HAM // Fantasy") {
+		t.Errorf("Topic should show the closest battle, got %s", output)
+	}
+}
+
+func TestHeadToHeadTable(t *testing.T) {
+	newFakeF1API(t, map[string]string{
+		"/2025/1/qualy": `{"races": {"qualyResults": [` + qualyJSON(1, "norris") + `, ` + qualyJSON(2, "piastri") + `]}}`,
+		"/2025/1/race":  `{"races": {"results": [` + resultJSON(1, 25, "norris", "Norris", "mclaren") + `, ` + resultJSON(2, 18, "piastri", "Piastri", "mclaren") + `]}}`,
+		"/2025/2/qualy": `{"races": {"qualyResults": [` + qualyJSON(1, "piastri") + `, ` + qualyJSON(3, "norris") + `]}}`,
+		"/2025/2/race":  `{"races": {"results": [` + resultJSON(2, 18, "norris", "Norris", "mclaren") + `, ` + resultJSON(9, 2, "piastri", "Piastri", "mclaren") + `]}}`,
+	})
+
+	data := profileData()
+	pairs := teammatePairs(data.Drivers)
+	addRoundResults(data, pairs)
+
+	expected := "Team                    Drivers     Points  Positions  Qualifying  Race\n" +
+		"McLaren Formula 1 Team  NOR vs PIA  44-34   P1-P4      1-1         2-0\n"
+	if got := headToHeadTable(pairs, true); got != expected {
+		t.Errorf("Unexpected table:\n%s\nwant:\n%s", got, expected)
+	}
+
+	expected = "Team                    Drivers     Points  Positions\n" +
+		"McLaren Formula 1 Team  NOR vs PIA  44-34   P1-P4\n"
+	if got := headToHeadTable(pairs, false); got != expected {
+		t.Errorf("Unexpected table without results:\n%s\nwant:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsDomain makes event UIDs globally unique
+const icsDomain = "just-vibes-f1-slack-topic"
+
+// session is a single session of a race weekend
+type session struct {
+	// key identifies the session in event UIDs, e.g. "qualy"
+	key      string
+	name     string
+	info     TimeInfo
+	duration time.Duration
+}
+
+// weekendSessions returns every kind of session a race weekend can have, whether or not this one does
+func weekendSessions(race *Race) []session {
+	return []session{
+		{"fp1", "Practice 1", race.Schedule.FP1, time.Hour},
+		{"fp2", "Practice 2", race.Schedule.FP2, time.Hour},
+		{"fp3", "Practice 3", race.Schedule.FP3, time.Hour},
+		{"sprint-qualy", "Sprint Qualifying", race.Schedule.SprintQualy, 45 * time.Minute},
+		{"sprint", "Sprint", race.Schedule.SprintRace, time.Hour},
+		{"qualy", "Qualifying", race.Schedule.Qualy, time.Hour},
+		{"race", "Race", race.Schedule.Race, 2 * time.Hour},
+	}
+}
+
+// raceSessions returns the sessions a race weekend has, in the order they run
+func raceSessions(race *Race) []session {
+	var sessions []session
+	for _, s := range weekendSessions(race) {
+		if _, _, err := parseSessionTime(s.info); err == nil {
+			sessions = append(sessions, s)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, _, _ := parseSessionTime(sessions[i].info)
+		b, _, _ := parseSessionTime(sessions[j].info)
+		return a.Before(b)
+	})
+	return sessions
+}
+
+// icsEscape escapes a TEXT value as RFC 5545 section 3.3.11 requires
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line to 75 octets as RFC 5545 section 3.1 requires, without splitting characters
+func icsFold(line string) string {
+	var sb strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = 74
+	}
+	sb.WriteString(line + "\r\n")
+	return sb.String()
+}
+
+// icsLocation describes where a race is held, e.g. "Suzuka International Racing Course, Suzuka, Japan"
+func icsLocation(race *Race) string {
+	var parts []string
+	for _, part := range []string{race.Circuit.CircuitName, race.Circuit.City, race.Circuit.Country} {
+		if part != "" && !containsString(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) < 3 && race.Country != "" && !containsString(parts, race.Country) {
+		parts = append(parts, race.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// buildICS returns an RFC 5545 calendar with an event for every session of the given races
+func buildICS(name string, races []Race) string {
+	var sb strings.Builder
+	line := func(format string, args ...any) {
+		sb.WriteString(icsFold(fmt.Sprintf(format, args...)))
+	}
+
+	stamp := now().UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//%s//EN", icsDomain)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", icsEscape(name))
+
+	for i := range races {
+		race := &races[i]
+		raceName := strings.TrimSpace(raceYearSuffix.ReplaceAllString(race.RaceName, ""))
+		for _, s := range raceSessions(race) {
+			start, hasTime, _ := parseSessionTime(s.info)
+
+			line("BEGIN:VEVENT")
+			line("UID:%s-%s@%s", race.RaceID, s.key, icsDomain)
+			line("DTSTAMP:%s", stamp)
+			if hasTime {
+				line("DTSTART:%s", start.Format("20060102T150405Z"))
+				line("DURATION:PT%dM", int(s.duration.Minutes()))
+			} else {
+				line("DTSTART;VALUE=DATE:%s", start.Format("20060102"))
+				line("DURATION:P1D")
+			}
+			line("SUMMARY:%s", icsEscape(fmt.Sprintf("F1: %s - %s", raceName, s.name)))
+			if location := icsLocation(race); location != "" {
+				line("LOCATION:%s", icsEscape(location))
+			}
+			line("END:VEVENT")
+		}
+	}
+
+	line("END:VCALENDAR")
+	return sb.String()
+}
+
+// icsCommand writes an iCalendar file of the season, or just the next race weekend
+func icsCommand(args []string) error {
+	fs := flag.NewFlagSet("ics", flag.ExitOnError)
+	next := fs.Bool("next", false, "Only include the next race weekend")
+	output := fs.String("o", "", "Write the calendar to this file instead of stdout")
+	fs.Parse(args)
+
+	calendar, err := fetchCalendar(snapshot.Season)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("F1 %d", calendar.Season)
+	races := calendar.Races
+	if *next {
+		race, _, err := nextRaceAfter(calendar.Races, snapshot.AsOfRound)
+		if err != nil {
+			return err
+		}
+		name = strings.TrimSpace(raceYearSuffix.ReplaceAllString(race.RaceName, ""))
+		races = []Race{*race}
+	}
+
+	ics := buildICS(name, races)
+	if *output == "" {
+		fmt.Print(ics)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(ics), 0o644); err != nil {
+		return fmt.Errorf("error writing calendar: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// sprintWeekend returns a race with a full sprint weekend schedule
+func sprintWeekend() Race {
+	return Race{
+		RaceID:   "chinese_2025",
+		RaceName: "Heineken Chinese Grand Prix 2025",
+		Schedule: Schedule{
+			FP1:         TimeInfo{Date: "2025-03-21", Time: "03:30:00Z"},
+			SprintQualy: TimeInfo{Date: "2025-03-21", Time: "07:30:00Z"},
+			SprintRace:  TimeInfo{Date: "2025-03-22", Time: "03:00:00Z"},
+			Qualy:       TimeInfo{Date: "2025-03-22", Time: "07:00:00Z"},
+			Race:        TimeInfo{Date: "2025-03-23", Time: "07:00:00Z"},
+		},
+		Circuit: Circuit{CircuitID: "shanghai", CircuitName: "Shanghai International Circuit", City: "Shanghai", Country: "China"},
+		Country: "China",
+	}
+}
+
+func TestRaceSessions(t *testing.T) {
+	race := sprintWeekend()
+
+	var keys []string
+	for _, s := range raceSessions(&race) {
+		keys = append(keys, s.key)
+	}
+	if got := strings.Join(keys, ","); got != "fp1,sprint-qualy,sprint,qualy,race" {
+		t.Errorf("Unexpected sessions: %s", got)
+	}
+}
+
+func TestBuildICS(t *testing.T) {
+	fixClock(t, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	races := []Race{sprintWeekend(), *testTopicData().NextRace}
+
+	ics := buildICS("F1 2025", races)
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:F1 2025\r\n",
+		"BEGIN:VEVENT\r\nUID:chinese_2025-sprint@just-vibes-f1-slack-topic\r\nDTSTAMP:20250301T120000Z\r\n" +
+			"DTSTART:20250322T030000Z\r\nDURATION:PT60M\r\nSUMMARY:F1: Heineken Chinese Grand Prix - Sprint\r\n" +
+			"LOCATION:Shanghai International Circuit\\, Shanghai\\, China\r\nEND:VEVENT\r\n",
+		"UID:japanese_2025-race@just-vibes-f1-slack-topic\r\nDTSTAMP:20250301T120000Z\r\nDTSTART:20250406T050000Z\r\nDURATION:PT120M\r\n",
+		"LOCATION:Suzuka International Racing Course\\, Japan\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Calendar should contain %q, got:\n%s", want, ics)
+		}
+	}
+	if count := strings.Count(ics, "BEGIN:VEVENT"); count != 7 {
+		t.Errorf("Expected one event per session, got %d", count)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line exceeds 75 octets: %q", line)
+		}
+	}
+}
+
+func TestBuildICSAllDay(t *testing.T) {
+	race := Race{RaceID: "tbc_2025", RaceName: "TBC Grand Prix", Schedule: Schedule{Race: TimeInfo{Date: "2025-11-30"}}}
+
+	ics := buildICS("F1", []Race{race})
+	if !strings.Contains(ics, "DTSTART;VALUE=DATE:20251130\r\nDURATION:P1D\r\n") {
+		t.Errorf("A race without a time should be an all-day event, got:\n%s", ics)
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("🏎", 30)
+	folded := icsFold(line)
+
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("Folded line exceeds 75 octets: %q", part)
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line+"\r\n" {
+		t.Errorf("Unfolding should give back the line, got %q", unfolded)
+	}
+}
+
+func TestICSEscape(t *testing.T) {
+	if got := icsEscape(`a,b;c\d` + "\ne"); got != `a\,b\;c\\d\ne` {
+		t.Errorf("icsEscape = %q", got)
+	}
+}
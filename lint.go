@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// emojiModifiers are shortcodes that decorate another emoji and so may be shared,
+// such as the marker worn by the reigning champions
+var emojiModifiers = map[string]bool{
+	":m1:": true,
+}
+
+// emojiValuePattern matches one or more shortcodes with nothing between them, e.g. ":m1::f1tl:"
+var emojiValuePattern = regexp.MustCompile(`^(:[a-z0-9_+'-]+:)+$`)
+
+// mapKeys returns the keys of a map
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// sortedKeys returns the union of the given key lists, sorted and without duplicates
+func sortedKeys(keyLists ...[]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, list := range keyLists {
+		for _, key := range list {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lintMappings checks the built-in and configured maps for duplicates, empty values and malformed shortcodes,
+// returning a description of each problem found. Built-in teams still missing an emoji are returned as warnings,
+// since they fall back rather than break the topic
+func lintMappings() (problems, warnings []string) {
+	emojiOwners := map[string][]string{}
+	abbrOwners := map[string][]string{}
+
+	// checkEmoji records an entity's emoji, reporting it if it's empty or malformed
+	checkEmoji := func(owner, emoji string) {
+		if emoji == "" {
+			problems = append(problems, fmt.Sprintf("%s has an empty emoji", owner))
+			return
+		}
+		if !emojiValuePattern.MatchString(emoji) {
+			problems = append(problems, fmt.Sprintf("%s has a malformed emoji %q, expected shortcodes like :f1tm:", owner, emoji))
+			return
+		}
+		for _, shortcode := range shortcodePattern.FindAllString(emoji, -1) {
+			if !emojiModifiers[shortcode] {
+				emojiOwners[shortcode] = append(emojiOwners[shortcode], owner)
+			}
+		}
+	}
+
+	teamIDs := sortedKeys(mapKeys(teamEmojis), mapKeys(config.TeamEmojis), mapKeys(config.TeamAbbrs))
+	for _, teamID := range teamIDs {
+		owner := "team " + teamID
+
+		// A team mapped with only an abbreviation or only an emoji falls back for the other, which isn't a problem
+		if emoji, exists := lookupTeamEmoji(teamID); exists {
+			checkEmoji(owner, emoji)
+		} else if teamEmojis[teamID].noEmoji {
+			warnings = append(warnings, fmt.Sprintf("%s has no built-in emoji and uses %s, set one with teamEmojis", owner, fallbackTeamEmoji))
+		}
+
+		abbr, exists := lookupTeamAbbr(teamID)
+		if !exists {
+			continue
+		}
+		if strings.TrimSpace(abbr) == "" {
+			problems = append(problems, fmt.Sprintf("%s has an empty abbreviation", owner))
+		} else {
+			abbrOwners[abbr] = append(abbrOwners[abbr], owner)
+		}
+	}
+
+	for _, driverID := range sortedKeys(mapKeys(driverEmojis), mapKeys(config.DriverEmojis)) {
+		emoji, _ := lookupDriverEmoji(driverID)
+		checkEmoji("driver "+driverID, emoji)
+	}
+
+	for _, key := range sortedKeys(mapKeys(config.RaceNames)) {
+		if strings.TrimSpace(config.RaceNames[key]) == "" {
+			problems = append(problems, fmt.Sprintf("race name for %s is empty", key))
+		}
+	}
+
+	for _, shortcode := range sortedKeys(mapKeys(emojiOwners)) {
+		if owners := emojiOwners[shortcode]; len(owners) > 1 {
+			problems = append(problems, fmt.Sprintf("emoji %s is used by %s", shortcode, strings.Join(owners, " and ")))
+		}
+	}
+	for _, abbr := range sortedKeys(mapKeys(abbrOwners)) {
+		if owners := abbrOwners[abbr]; len(owners) > 1 {
+			problems = append(problems, fmt.Sprintf("abbreviation %s is used by %s", abbr, strings.Join(owners, " and ")))
+		}
+	}
+
+	return problems, warnings
+}
+
+// validateMappings exits if the emoji and abbreviation maps have any problems, so collisions never reach the topic
+func validateMappings() {
+	problems, _ := lintMappings()
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Invalid emoji mappings (run the lint command for details):")
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "  %s\n", problem)
+	}
+	os.Exit(2)
+}
+
+// lintCommand checks the whole mapping config and reports every problem found
+func lintCommand(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.Parse(args)
+
+	problems, warnings := lintMappings()
+	for _, warning := range warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return nil
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	return fmt.Errorf("%d problems found", len(problems))
+}
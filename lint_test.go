@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintMappingsBuiltIn(t *testing.T) {
+	problems, warnings := lintMappings()
+	if len(problems) > 0 {
+		t.Errorf("Built-in mappings should be clean, got:\n%s", strings.Join(problems, "\n"))
+	}
+	expected := []string{"team alpine has no built-in emoji and uses :racing_car:, set one with teamEmojis"}
+	if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected a warning for each built-in team without an emoji, got:\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestLintMappingsEmptyBuiltIn(t *testing.T) {
+	original := teamEmojis["williams"]
+	defer func() { teamEmojis["williams"] = original }()
+	entry := original
+	entry.emoji = ""
+	teamEmojis["williams"] = entry
+
+	problems, _ := lintMappings()
+	if !strings.Contains(strings.Join(problems, "\n"), "team williams has an empty emoji") {
+		t.Errorf("An empty built-in emoji should be a problem, got:\n%s", strings.Join(problems, "\n"))
+	}
+}
+
+func TestLintMappingsConfiguredAlpine(t *testing.T) {
+	defer func() { config = Config{} }()
+	config = Config{TeamEmojis: map[string]string{"alpine": ":alpine_f1:"}}
+
+	if problems, warnings := lintMappings(); len(problems)+len(warnings) > 0 {
+		t.Errorf("Alpine with a configured emoji should be clean, got:\n%s", strings.Join(append(problems, warnings...), "\n"))
+	}
+}
+
+func TestLintMappingsConfig(t *testing.T) {
+	defer func() { config = Config{} }()
+	config = Config{
+		TeamEmojis:   map[string]string{"alpine": ":f1ta:", "cadillac": "f1tc"},
+		TeamAbbrs:    map[string]string{"cadillac": ""},
+		DriverEmojis: map[string]string{"lindblad": ":f1gr:", "colapinto": ""},
+		RaceNames:    map[string]string{"madring": " "},
+	}
+
+	found, _ := lintMappings()
+	problems := strings.Join(found, "\n")
+	expected := []string{
+		"emoji :f1ta: is used by team alpine and team aston_martin",
+		"emoji :f1gr: is used by driver lindblad and driver russell",
+		`team cadillac has a malformed emoji "f1tc"`,
+		"team cadillac has an empty abbreviation",
+		"driver colapinto has an empty emoji",
+		"race name for madring is empty",
+	}
+	for _, want := range expected {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Problems should contain %q, got:\n%s", want, problems)
+		}
+	}
+}
+
+func TestLintMappingsPartialTeam(t *testing.T) {
+	defer func() { config = Config{} }()
+	config = Config{
+		TeamAbbrs:  map[string]string{"cadillac": "CAD"},
+		TeamEmojis: map[string]string{"audi": ":f1tau:"},
+	}
+
+	// Teams with only an abbreviation or only an emoji get the fallback for the other
+	if problems, _ := lintMappings(); len(problems) > 0 {
+		t.Errorf("A team with only an abbreviation or emoji should be clean, got:\n%s", strings.Join(problems, "\n"))
+	}
+}
+
+func TestLintMappingsModifiers(t *testing.T) {
+	defer func() { config = Config{} }()
+	config = Config{TeamEmojis: map[string]string{"ferrari": ":m1::f1tf:"}}
+
+	if problems, _ := lintMappings(); len(problems) > 0 {
+		t.Errorf("The champion marker should be shareable, got:\n%s", strings.Join(problems, "\n"))
+	}
+}
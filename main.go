@@ -2,14 +2,16 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
-	"time"
+	"sync"
 )
 
 // F1 API base URL
@@ -91,10 +93,15 @@ type Race struct {
 	Sprint         bool     `json:"sprint"`
 }
 
-// Schedule represents the schedule for a race weekend
+// Schedule represents the schedule for a race weekend, sessions a weekend doesn't have are left empty
 type Schedule struct {
-	Race  TimeInfo `json:"race"`
-	Qualy TimeInfo `json:"qualy"`
+	Race        TimeInfo `json:"race"`
+	Qualy       TimeInfo `json:"qualy"`
+	FP1         TimeInfo `json:"fp1"`
+	FP2         TimeInfo `json:"fp2"`
+	FP3         TimeInfo `json:"fp3"`
+	SprintQualy TimeInfo `json:"sprintQualy"`
+	SprintRace  TimeInfo `json:"sprintRace"`
 }
 
 // TimeInfo contains date and time information
@@ -107,9 +114,15 @@ type TimeInfo struct {
 type Circuit struct {
 	CircuitID   string  `json:"circuitId"`
 	CircuitName string  `json:"circuitName"`
+	Country     string  `json:"country"`
+	City        string  `json:"city"`
 	Length      float64 `json:"length"`
 	Laps        int     `json:"laps"`
 	Laprecord   string  `json:"lapRecord,omitempty"`
+	// Lap record holder and year, and the year of the circuit's first Grand Prix
+	FastestLapDriverID     string `json:"fastestLapDriverId,omitempty"`
+	FastestLapYear         int    `json:"fastestLapYear,omitempty"`
+	FirstParticipationYear int    `json:"firstParticipationYear,omitempty"`
 }
 
 // Driver represents a Formula 1 driver
@@ -142,21 +155,23 @@ type ErrorResponse struct {
 	Status  int    `json:"status"`
 }
 
-// Map team IDs to team emojis and abbreviations
+// Map team IDs to team emojis and abbreviations. Teams marked noEmoji have no workspace emoji yet,
+// so they use the fallback unless the config sets one
 var teamEmojis = map[string]struct {
-	emoji string
-	abbr  string
+	emoji   string
+	abbr    string
+	noEmoji bool
 }{
-	"mclaren":      {":m1::f1tl:", "MCL"},
-	"mercedes":     {":f1tm:", "MER"},
-	"red_bull":     {":f1tr:", "RBR"},
-	"ferrari":      {":f1tf:", "FER"},
-	"aston_martin": {":f1ta:", "AST"},
-	"williams":     {":f1tw:", "WIL"},
-	"alpine":       {":f1ta:", "ALP"},
-	"haas":         {":f1th:", "HAA"},
-	"rb":           {":f1tb:", "RB"},
-	"sauber":       {":f1ts:", "SAU"},
+	"mclaren":      {emoji: ":m1::f1tl:", abbr: "MCL"},
+	"mercedes":     {emoji: ":f1tm:", abbr: "MER"},
+	"red_bull":     {emoji: ":f1tr:", abbr: "RBR"},
+	"ferrari":      {emoji: ":f1tf:", abbr: "FER"},
+	"aston_martin": {emoji: ":f1ta:", abbr: "AST"},
+	"williams":     {emoji: ":f1tw:", abbr: "WIL"},
+	"alpine":       {abbr: "ALP", noEmoji: true},
+	"haas":         {emoji: ":f1th:", abbr: "HAA"},
+	"rb":           {emoji: ":f1tb:", abbr: "RB"},
+	"sauber":       {emoji: ":f1ts:", abbr: "SAU"},
 }
 
 // Map driver IDs to driver emojis
@@ -177,86 +192,57 @@ var driverEmojis = map[string]string{
 	"gasly":          ":f1pg:",
 }
 
-// Map country names to flag emojis
-var countryFlags = map[string]string{
-	"Great Britain": ":gb:",
-	"Netherlands":   ":flag-nl:",
-	"Australia":     ":flag-au:",
-	"Monaco":        ":flag-mc:",
-	"Spain":         ":flag-es:",
-	"Mexico":        ":flag-mx:",
-	"Canada":        ":flag-ca:",
-	"Japan":         ":flag-jp:",
-	"France":        ":flag-fr:",
-	"Thailand":      ":flag-th:",
-	"China":         ":flag-cn:",
-	"United States": ":flag-us:",
-	"Italy":         ":flag-it:",
-	"Germany":       ":flag-de:",
-}
+// apiBaseURL is the F1 API base URL, a variable so tests can point it at a local server
+var apiBaseURL = BaseURL
+
+// errNoData is wrapped by fetchAPI when the API responds that it has no data for a request
+var errNoData = errors.New("no data found")
 
-// Map countries to their 2-letter codes for flag emojis
-var countryTwoLetterCodes = map[string]string{
-	"China":                "cn",
-	"Japan":                "jp",
-	"Great Britain":        "gb",
-	"United Kingdom":       "gb",
-	"United States":        "us",
-	"USA":                  "us",
-	"Australia":            "au",
-	"Netherlands":          "nl",
-	"Italy":                "it",
-	"France":               "fr",
-	"Germany":              "de",
-	"Spain":                "es",
-	"Monaco":               "mc",
-	"Canada":               "ca",
-	"Mexico":               "mx",
-	"Brazil":               "br",
-	"Austria":              "at",
-	"Belgium":              "be",
-	"Hungary":              "hu",
-	"Singapore":            "sg",
-	"Russia":               "ru",
-	"Azerbaijan":           "az",
-	"Bahrain":              "bh",
-	"United Arab Emirates": "ae",
-	"Abu Dhabi":            "ae",
-	"Qatar":                "qa",
-	"Saudi Arabia":         "sa",
-	"Thailand":             "th",
-	"Switzerland":          "ch",
+// seasonPath returns the API path segment for a season, where 0 is the current season
+func seasonPath(season int) string {
+	if season == 0 {
+		return "current"
+	}
+	return strconv.Itoa(season)
 }
 
-// fetchNextRace gets the next race in the calendar and its round number
-func fetchNextRace() (*Race, int, error) {
-	url := fmt.Sprintf("%s/current/next", BaseURL)
+// fetchAPI gets an F1 API path and unmarshals the response into out, naming the data what in logs and errors
+func fetchAPI(path, what string, out any) error {
+	url := apiBaseURL + path
 
-	log.Printf("Fetching next race data from: %s", url)
+	log.Printf("Fetching %s from: %s", what, url)
 	resp, err := http.Get(url)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error fetching next race: %v", err)
+		return fmt.Errorf("error fetching %s: %v", what, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error reading response: %v", err)
+		return fmt.Errorf("error reading response: %v", err)
 	}
 
 	// Log a truncated version of the response for debugging
 	truncLen := min(len(body), 500)
-	log.Printf("Next race API response (truncated): %s", string(body[:truncLen]))
+	log.Printf("API response for %s (truncated): %s", what, string(body[:truncLen]))
 
 	// Check if we got an error response
 	var errorResp ErrorResponse
 	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Status >= 400 {
-		return nil, 0, fmt.Errorf("no data found: %s", errorResp.Message)
+		return fmt.Errorf("%w: %s", errNoData, errorResp.Message)
 	}
 
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling %s: %v", what, err)
+	}
+	return nil
+}
+
+// fetchNextRace gets the next race in the current calendar and its round number
+func fetchNextRace() (*Race, int, error) {
 	var nextRaceResp NextRaceResponse
-	if err := json.Unmarshal(body, &nextRaceResp); err != nil {
-		return nil, 0, fmt.Errorf("error unmarshaling next race data: %v", err)
+	if err := fetchAPI("/current/next", "next race data", &nextRaceResp); err != nil {
+		return nil, 0, err
 	}
 
 	if len(nextRaceResp.Race) == 0 {
@@ -266,71 +252,23 @@ func fetchNextRace() (*Race, int, error) {
 	return &nextRaceResp.Race[0], nextRaceResp.Round, nil
 }
 
-// fetchDriverStandings gets the current driver championship standings
-func fetchDriverStandings() ([]DriverStanding, error) {
-	url := fmt.Sprintf("%s/current/drivers-championship", BaseURL)
-
-	log.Printf("Fetching driver standings from: %s", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching driver standings: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
-	}
-
-	// Log a truncated version of the response for debugging
-	truncLen := min(len(body), 500)
-	log.Printf("Driver standings API response (truncated): %s", string(body[:truncLen]))
-
-	// Check if we got an error response
-	var errorResp ErrorResponse
-	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Status >= 400 {
-		return nil, fmt.Errorf("no data found: %s", errorResp.Message)
-	}
-
+// fetchDriverStandings gets a season's driver championship standings, where season 0 is the current season
+func fetchDriverStandings(season int) ([]DriverStanding, error) {
 	var driverResp DriverChampionshipResponse
-	if err := json.Unmarshal(body, &driverResp); err != nil {
-		return nil, fmt.Errorf("error unmarshaling driver data: %v", err)
+	path := fmt.Sprintf("/%s/drivers-championship", seasonPath(season))
+	if err := fetchAPI(path, "driver standings", &driverResp); err != nil {
+		return nil, err
 	}
-
 	return driverResp.DriversChampionship, nil
 }
 
-// fetchTeamStandings gets the current constructor championship standings
-func fetchTeamStandings() ([]TeamStanding, error) {
-	url := fmt.Sprintf("%s/current/constructors-championship", BaseURL)
-
-	log.Printf("Fetching team standings from: %s", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching team standings: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
-	}
-
-	// Log a truncated version of the response for debugging
-	truncLen := min(len(body), 500)
-	log.Printf("Team standings API response (truncated): %s", string(body[:truncLen]))
-
-	// Check if we got an error response
-	var errorResp ErrorResponse
-	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Status >= 400 {
-		return nil, fmt.Errorf("no data found: %s", errorResp.Message)
-	}
-
+// fetchTeamStandings gets a season's constructor championship standings, where season 0 is the current season
+func fetchTeamStandings(season int) ([]TeamStanding, error) {
 	var teamResp ConstructorChampionshipResponse
-	if err := json.Unmarshal(body, &teamResp); err != nil {
-		return nil, fmt.Errorf("error unmarshaling team data: %v", err)
+	path := fmt.Sprintf("/%s/constructors-championship", seasonPath(season))
+	if err := fetchAPI(path, "team standings", &teamResp); err != nil {
+		return nil, err
 	}
-
 	return teamResp.ConstructorsChampionship, nil
 }
 
@@ -342,277 +280,169 @@ func min(a, b int) int {
 	return b
 }
 
-// Topic builds the F1 information string
-func Topic() string {
-	var sb strings.Builder
+// warned records which warnings have already been logged
+var warned sync.Map
 
-	// Get current season year
-	currentYear := time.Now().Year()
-	sb.WriteString(fmt.Sprintf("F1 Data for %d\n\n", currentYear))
+// warnOnce logs a warning the first time it's called with a given message
+func warnOnce(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if _, seen := warned.LoadOrStore(msg, true); !seen {
+		log.Printf("WARNING: %s", msg)
+	}
+}
 
-	// Get and display next race
-	nextRace, round, err := fetchNextRace()
-	if err != nil {
-		log.Printf("Error getting next race: %v", err)
-		sb.WriteString(fmt.Sprintf("Next race: %v\n\n", err))
-	} else {
-		raceDate, err := time.Parse("2006-01-02", nextRace.Schedule.Race.Date)
-		if err != nil {
-			log.Printf("Error parsing race date: %v", err)
-			sb.WriteString("Next Race: Unknown (date parsing error)\n\n")
-		} else {
-			raceTime, timeErr := time.Parse("15:04:05Z", nextRace.Schedule.Race.Time)
-			timeStr := ""
-			if timeErr == nil {
-				timeStr = fmt.Sprintf(" at %s", raceTime.Format("15:04 MST"))
-			}
+// TopicData holds the data fetched from the API that renderers turn into output
+type TopicData struct {
+	Season      int
+	TotalRaces  int
+	Calendar    []Race
+	NextRace    *Race
+	Round       int
+	NextRaceErr error
+	// Grid is the qualifying classification for the next race, only fetched between qualifying and the race
+	Grid       []QualifyingResult
+	GridErr    error
+	Drivers    []DriverStanding
+	DriversErr error
+	Teams      []TeamStanding
+	TeamsErr   error
+	Fantasy    []FantasyEntry
+	FantasyErr error
+
+	// OffSeason is set once every race in the season has run, with round 1 of the next season as SeasonOpener
+	OffSeason       bool
+	SeasonOpener    *Race
+	SeasonOpenerErr error
+
+	// CalendarChanges lists changes to the calendar since it was last seen
+	CalendarChanges []calendarChange
+}
 
-			sb.WriteString(fmt.Sprintf("Next Race: %s (Round %d)\n", nextRace.RaceName, round))
-			sb.WriteString(fmt.Sprintf("Circuit: %s\n", nextRace.Circuit.CircuitName))
-			sb.WriteString(fmt.Sprintf("Date: %s%s\n", raceDate.Format("January 2, 2006"), timeStr))
-			sb.WriteString(fmt.Sprintf("Country: %s\n\n", nextRace.Country))
-		}
-	}
+// fetchTopicData fetches the next race and championship standings in one go, for the season and round
+// selected by snapshot
+func fetchTopicData() *TopicData {
+	data := &TopicData{Season: snapshot.Season}
 
-	// Get and display driver standings
-	drivers, err := fetchDriverStandings()
-	if err != nil {
-		log.Printf("Error fetching driver standings: %v", err)
-		sb.WriteString(fmt.Sprintf("Driver standings error: %v\n\n", err))
+	// The calendar gives the season the API is on, which differs from the wall clock in the off-season
+	calendar, calendarErr := fetchCalendar(snapshot.Season)
+	if calendarErr != nil {
+		log.Printf("Error fetching season calendar: %v", calendarErr)
 	} else {
-		sb.WriteString("Driver Standings:\n")
-		for i := 0; i < 3 && i < len(drivers); i++ {
-			driver := drivers[i]
-			sb.WriteString(fmt.Sprintf("%d. %s %s (%s) - %.1f points\n",
-				driver.Position, driver.Driver.Name, driver.Driver.Surname, driver.Team.TeamName, driver.Points))
+		data.Season = calendar.Season
+		data.TotalRaces = len(calendar.Races)
+		data.Calendar = calendar.Races
+		if snapshot.live() {
+			data.CalendarChanges = checkCalendarChanges(calendar)
 		}
-		sb.WriteString("\n")
 	}
-
-	// Get and display constructor/team standings
-	teams, err := fetchTeamStandings()
-	if err != nil {
-		log.Printf("Error fetching team standings: %v", err)
-		sb.WriteString(fmt.Sprintf("Constructor standings error: %v\n", err))
-	} else {
-		sb.WriteString("Constructor Standings:\n")
-		for i := 0; i < 3 && i < len(teams); i++ {
-			team := teams[i]
-			sb.WriteString(fmt.Sprintf("%d. %s - %.1f points\n",
-				team.Position, team.Team.TeamName, team.Points))
-		}
+	if data.Season == 0 {
+		data.Season = now().Year()
 	}
 
-	return sb.String()
-}
-
-// SlackTopic builds a compact Slack topic with emojis for F1 information
-func SlackTopic() string {
-	var sb strings.Builder
-
-	// Get current season year
-	currentYear := time.Now().Year()
-	totalRaces := 24 // Hardcoded for now, could be retrieved from API
-
-	// Start with F1 emoji and year
-	sb.WriteString(fmt.Sprintf(":f1: %d ", currentYear))
-
-	// Get next race
-	nextRace, round, err := fetchNextRace()
-	if err != nil {
-		log.Printf("Error getting next race: %v", err)
-		sb.WriteString("Next: No upcoming races // ")
+	if snapshot.live() {
+		data.NextRace, data.Round, data.NextRaceErr = fetchNextRace()
+		data.Drivers, data.DriversErr = fetchDriverStandings(0)
+		data.Teams, data.TeamsErr = fetchTeamStandings(0)
 	} else {
-		// Parse race date
-		raceDate, err := time.Parse("2006-01-02", nextRace.Schedule.Race.Date)
-		if err != nil {
-			log.Printf("Error parsing race date: %v", err)
-			sb.WriteString(fmt.Sprintf("Next: R%d/%d %s // ", round, totalRaces, extractRaceName(nextRace.RaceName)))
+		if calendarErr != nil {
+			data.NextRaceErr = calendarErr
 		} else {
-			// Get country code for flag emoji
-			countryCode := "unknown"
-
-			// First try to determine country from race name if the Country field is empty
-			raceName := extractRaceName(nextRace.RaceName)
-			if raceName == "Japan" || strings.Contains(strings.ToLower(nextRace.RaceName), "japanese") {
-				countryCode = "jp"
-			} else if raceName == "China" || strings.Contains(strings.ToLower(nextRace.RaceName), "chinese") {
-				countryCode = "cn"
-			} else if len(nextRace.Country) > 0 {
-				// If Country field is set, try to get the code from our map
-				if code, exists := countryTwoLetterCodes[nextRace.Country]; exists {
-					countryCode = code
-				} else {
-					// Default to first two letters of country name, lowercase
-					if len(nextRace.Country) >= 2 {
-						countryCode = strings.ToLower(nextRace.Country[0:2])
-					}
-				}
-			}
-
-			// Calculate race weekend dates (Friday-Sunday)
-			raceWeekendStart := raceDate.AddDate(0, 0, -2) // Friday is typically 2 days before race day (Sunday)
-
-			// Format as "Next: R[round]/24 [race] :flag-xx: (Mon DD-DD)"
-			sb.WriteString(fmt.Sprintf("Next: R%d/%d %s :flag-%s: (%s %d-%d) // ",
-				round,
-				totalRaces,
-				extractRaceName(nextRace.RaceName),
-				countryCode,
-				raceWeekendStart.Format("Jan"),
-				raceWeekendStart.Day(),
-				raceDate.Day()))
+			data.NextRace, data.Round, data.NextRaceErr = nextRaceAfter(calendar.Races, snapshot.AsOfRound)
 		}
-	}
 
-	// Get driver standings
-	drivers, err := fetchDriverStandings()
-	if err != nil {
-		log.Printf("Error fetching driver standings: %v", err)
-		sb.WriteString("Standings: No data // ")
-	} else {
-		sb.WriteString("Standings: ")
-
-		// Add top 3 drivers
-		for i := 0; i < 3 && i < len(drivers); i++ {
-			driver := drivers[i]
-
-			// Get driver emoji
-			driverEmoji := ""
-			if emoji, exists := driverEmojis[driver.DriverID]; exists {
-				driverEmoji = emoji
-			}
-
-			// Get flag emoji
-			flagEmoji := ":flag-xx:"
-			if flag, exists := countryFlags[driver.Driver.Nationality]; exists {
-				flagEmoji = flag
-			}
-
-			// Format as "[driverEmoji]ABBR flagEmoji (points)"
-			sb.WriteString(fmt.Sprintf("%s%s %s (%.0f)",
-				driverEmoji,
-				driver.Driver.ShortName,
-				flagEmoji,
-				driver.Points))
-
-			// Add comma if not the last driver
-			if i < 2 && i+1 < len(drivers) {
-				sb.WriteString(", ")
+		if snapshot.AsOfRound > 0 {
+			if calendarErr != nil {
+				data.DriversErr, data.TeamsErr = calendarErr, calendarErr
+			} else {
+				data.Drivers, data.Teams, data.DriversErr = standingsAsOf(data.Season, calendar.Races, snapshot.AsOfRound)
+				data.TeamsErr = data.DriversErr
 			}
+		} else {
+			data.Drivers, data.DriversErr = fetchDriverStandings(snapshot.Season)
+			data.Teams, data.TeamsErr = fetchTeamStandings(snapshot.Season)
 		}
+	}
 
-		// Separator between drivers and constructors
-		sb.WriteString("; ")
+	// Between qualifying and the race, fetch the grid
+	if data.NextRaceErr == nil && data.NextRace != nil && snapshot.AsOfRound == 0 && inGridWindow(data.NextRace, now()) {
+		data.Grid, data.GridErr = fetchQualifyingResults(data.Season, data.Round)
+		if errors.Is(data.GridErr, errResultsNotPublished) {
+			log.Printf("Qualifying results not yet published")
+			data.GridErr = nil
+		} else if data.GridErr != nil {
+			log.Printf("Error fetching qualifying results: %v", data.GridErr)
+		}
 	}
 
-	// Get constructor standings
-	teams, err := fetchTeamStandings()
-	if err != nil {
-		log.Printf("Error fetching team standings: %v", err)
-		sb.WriteString("No constructor data // ")
-	} else {
-		// Add top 3 constructors
-		for i := 0; i < 3 && i < len(teams); i++ {
-			team := teams[i]
-
-			// Get team emoji and abbreviation
-			teamInfo := teamEmojis["unknown"]
-			if info, exists := teamEmojis[team.TeamID]; exists {
-				teamInfo = info
-			}
+	// Between the final race and the next season, show the champions and a countdown instead
+	if snapshot.live() {
+		markOffSeason(data, calendar)
+	}
 
-			// Format as "teamEmoji ABBR (points)"
-			sb.WriteString(fmt.Sprintf("%s%s (%.0f)",
-				teamInfo.emoji,
-				teamInfo.abbr,
-				team.Points))
+	if data.NextRaceErr != nil {
+		log.Printf("Error getting next race: %v", data.NextRaceErr)
+	}
+	if data.DriversErr != nil {
+		log.Printf("Error fetching driver standings: %v", data.DriversErr)
+	}
+	if data.TeamsErr != nil {
+		log.Printf("Error fetching team standings: %v", data.TeamsErr)
+	}
 
-			// Add comma if not the last team
-			if i < 2 && i+1 < len(teams) {
-				sb.WriteString(", ")
-			}
+	if url := config.Fantasy.LeaderboardURL; url != "" && !config.Fantasy.Disabled {
+		data.Fantasy, data.FantasyErr = fetchFantasyLeaderboard(url)
+		if data.FantasyErr != nil {
+			log.Printf("Error fetching fantasy leaderboard: %v", data.FantasyErr)
 		}
 	}
 
-	// Add fantasy code
-	sb.WriteString(" // Fantasy: `thanksai`")
-
-	// Get the final topic string
-	topic := sb.String()
+	return data
+}
 
-	// Check if the topic exceeds the 250 character limit
-	// Slack counts each character, including emoji codes (e.g., ":flag-jp:" is 9 characters)
-	if len(topic) > 250 {
-		log.Printf("WARNING: Slack topic exceeds 250 character limit (%d characters)", len(topic))
-		return fmt.Sprintf("ERROR: Slack topic exceeds 250 character limit (%d characters)", len(topic))
-	}
+// Topic builds the F1 information string
+func Topic() string {
+	data := fetchTopicData()
+	fetchCircuitDetails(data)
+	return textRenderer{}.Render(data)
+}
 
-	return topic
+// SlackTopic builds a compact Slack topic with emojis for F1 information
+func SlackTopic() string {
+	return slackRenderer{}.Render(fetchTopicData())
 }
 
-// extractRaceName extracts the main part of the race name (e.g., "Lenovo Japanese Grand Prix 2025" -> "Japan")
-func extractRaceName(fullName string) string {
-	// This is a simple implementation - could be more sophisticated
-	if strings.Contains(fullName, "Japanese") {
-		return "Japan"
-	} else if strings.Contains(fullName, "Chinese") {
-		return "China"
-	} else if strings.Contains(fullName, "Monaco") {
-		return "Monaco"
-	} else if strings.Contains(fullName, "British") {
-		return "Britain"
-	} else if strings.Contains(fullName, "Italian") {
-		return "Italy"
-	} else if strings.Contains(fullName, "Spanish") {
-		return "Spain"
-	} else if strings.Contains(fullName, "Australian") {
-		return "Australia"
-	} else if strings.Contains(fullName, "Hungarian") {
-		return "Hungary"
-	} else if strings.Contains(fullName, "Belgian") {
-		return "Belgium"
-	} else if strings.Contains(fullName, "Dutch") {
-		return "Netherlands"
-	} else if strings.Contains(fullName, "United States") || strings.Contains(fullName, "USA") {
-		return "USA"
-	} else if strings.Contains(fullName, "Mexico") || strings.Contains(fullName, "Mexican") {
-		return "Mexico"
-	} else if strings.Contains(fullName, "Brazil") || strings.Contains(fullName, "Brazilian") || strings.Contains(fullName, "São Paulo") {
-		return "Brazil"
-	} else if strings.Contains(fullName, "Abu Dhabi") {
-		return "Abu Dhabi"
-	} else if strings.Contains(fullName, "Qatar") {
-		return "Qatar"
-	} else if strings.Contains(fullName, "Singapore") {
-		return "Singapore"
-	} else if strings.Contains(fullName, "Saudi") || strings.Contains(fullName, "Jeddah") {
-		return "Saudi Arabia"
-	} else if strings.Contains(fullName, "Bahrain") {
-		return "Bahrain"
-	} else if strings.Contains(fullName, "Miami") {
-		return "Miami"
-	} else if strings.Contains(fullName, "Las Vegas") {
-		return "Las Vegas"
-	} else if strings.Contains(fullName, "Canada") || strings.Contains(fullName, "Canadian") || strings.Contains(fullName, "Montreal") {
-		return "Canada"
-	} else if strings.Contains(fullName, "Azerbaijan") || strings.Contains(fullName, "Baku") {
-		return "Azerbaijan"
-	}
-
-	// Return a shortened version if no specific match
-	parts := strings.Split(fullName, " ")
-	if len(parts) > 2 {
-		return strings.Join(parts[1:len(parts)-2], " ") // Skip first word (usually a sponsor) and last two (usually "Grand Prix YYYY")
-	}
-	return fullName
+// Map subcommand names to their implementations
+var commands = map[string]func(args []string) error{
+	"post":        postCommand,
+	"unmapped":    unmappedCommand,
+	"check-emoji": checkEmojiCommand,
+	"lint":        lintCommand,
+	"calendar":    calendarCommand,
+	"ics":         icsCommand,
+	"serve":       serveCommand,
+	"remind":      remindCommand,
+	"summary":     summaryCommand,
+	"driver":      driverCommand,
+	"team":        teamCommand,
+	"h2h":         h2hCommand,
 }
 
 func main() {
 	// Define command-line flags
-	detailed := flag.Bool("detailed", true, "Show detailed output (default)")
+	flag.Bool("detailed", true, "Show detailed output (default)")
 	slackFormat := flag.Bool("slack", false, "Show Slack topic format")
+	format := flag.String("format", "", "Output format: "+strings.Join(rendererNames(), ", ")+" (overrides -detailed and -slack)")
+	emojiStyle := flag.String("emoji-style", "", "Flag style: "+strings.Join(flagStyleNames(), ", ")+" (defaults to shortcode for Slack formats and unicode otherwise)")
 	quiet := flag.Bool("quiet", false, "Suppress log messages")
+	configPath := flag.String("config", "", "Path to a JSON config file")
+	emojiFallback := flag.Bool("emoji-fallback", false, "Check custom emoji with emoji.list and use text for any missing from the workspace (needs -token or $SLACK_TOKEN)")
+	publishTarget := flag.String("publish", "stdout", "Publish target: "+strings.Join(publisherNames(), ", "))
+	flag.StringVar(&publishOpts.WebhookURL, "webhook-url", "", "Incoming webhook URL for -publish webhook, discord and teams (defaults to $SLACK_WEBHOOK_URL, $DISCORD_WEBHOOK_URL or $TEAMS_WEBHOOK_URL)")
+	addSlackFlags(flag.CommandLine)
+	flag.IntVar(&snapshot.Season, "season", 0, "Season to show, e.g. 2023 (defaults to the current season)")
+	flag.IntVar(&snapshot.AsOfRound, "as-of-round", 0, "Show standings as they were after this round, with the following round as next race")
+	flag.StringVar(&stateDir, "state-dir", defaultStateDir(), "Directory for state kept between runs, such as the last-seen calendar")
+	calendarNoticeFlag := flag.Bool("calendar-notice", false, "Post a notice to -channel when the calendar changes (needs -token or $SLACK_TOKEN)")
 	flag.Parse()
 
 	// If -quiet flag is set, disable logging
@@ -620,20 +450,82 @@ func main() {
 		log.SetOutput(io.Discard)
 	}
 
-	// Choose output format based on flags
-	if *slackFormat {
-		*detailed = false
-		topic := SlackTopic()
-		fmt.Println(topic)
+	if *configPath != "" {
+		cfg, err := loadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		config = cfg
+	}
+
+	if snapshot.Season < 0 || snapshot.AsOfRound < 0 {
+		fmt.Fprintln(os.Stderr, "-season and -as-of-round must not be negative")
+		os.Exit(2)
+	}
 
-		// Check if topic contains an error about exceeding character limit
-		if strings.HasPrefix(topic, "ERROR:") {
+	// The lint command reports mapping problems itself, everything else refuses to run with them
+	if flag.Arg(0) != "lint" {
+		validateMappings()
+	}
+
+	if *emojiFallback {
+		if err := loadMissingEmoji(publishOpts.Token); err != nil {
+			log.Printf("Error checking workspace emoji, leaving emoji as they are: %v", err)
+		}
+	}
+
+	// Run a subcommand if one was given
+	if flag.NArg() > 0 {
+		command, ok := commands[flag.Arg(0)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown command %q\n", flag.Arg(0))
+			os.Exit(2)
+		}
+		if err := command(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(0), err)
 			os.Exit(1)
 		}
-	} else if *detailed {
-		fmt.Println(Topic())
-	} else {
-		// Default to detailed if no format is specified
-		fmt.Println(Topic())
+		return
+	}
+
+	if *format == "" {
+		*format = defaultFormat(*slackFormat, *publishTarget)
+	}
+
+	renderer, ok := renderers[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, expected one of: %s\n", *format, strings.Join(rendererNames(), ", "))
+		os.Exit(2)
+	}
+
+	if *emojiStyle != "" {
+		style, err := parseFlagStyle(*emojiStyle)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		if r, ok := renderer.(flagStyler); ok {
+			renderer = r.withFlags(style)
+		}
+	}
+
+	publisher, err := newPublisher(*publishTarget, renderer, publishOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+
+	data := fetchTopicData()
+	if _, detailed := renderer.(textRenderer); detailed {
+		fetchCircuitDetails(data)
+	}
+
+	reportCalendarChanges(data, *calendarNoticeFlag, publishOpts)
+
+	// Exits non-zero if the topic exceeds the character limit or publishing fails
+	if err := publisher.Publish(data); err != nil {
+		log.Printf("Error publishing: %v", err)
+		os.Exit(1)
 	}
 }
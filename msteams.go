@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// adaptiveCard is a Microsoft Adaptive Card, as rendered by Teams
+type adaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []cardElement `json:"body"`
+}
+
+// cardElement is an Adaptive Card body element, either a TextBlock or a FactSet
+type cardElement struct {
+	Type      string     `json:"type"`
+	Text      string     `json:"text,omitempty"`
+	Size      string     `json:"size,omitempty"`
+	Weight    string     `json:"weight,omitempty"`
+	Wrap      bool       `json:"wrap,omitempty"`
+	IsSubtle  bool       `json:"isSubtle,omitempty"`
+	Separator bool       `json:"separator,omitempty"`
+	Facts     []cardFact `json:"facts,omitempty"`
+}
+
+// cardFact is a title/value pair in a FactSet
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// msTeamsMessage wraps an Adaptive Card as a message for a Teams incoming webhook
+type msTeamsMessage struct {
+	Type        string              `json:"type"`
+	Attachments []msTeamsAttachment `json:"attachments"`
+}
+
+// msTeamsAttachment is a card attached to a Teams message
+type msTeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+// newMSTeamsMessage wraps a card in the envelope Teams webhooks expect
+func newMSTeamsMessage(card adaptiveCard) msTeamsMessage {
+	return msTeamsMessage{
+		Type: "message",
+		Attachments: []msTeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
+
+// newAdaptiveCard creates an empty card using the schema version Teams supports
+func newAdaptiveCard() adaptiveCard {
+	return adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+	}
+}
+
+// cardRenderer is implemented by renderers that can produce an Adaptive Card
+type cardRenderer interface {
+	Card(data *TopicData) adaptiveCard
+}
+
+// msTeamsRenderer renders a Teams Adaptive Card message payload as JSON
+type msTeamsRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r msTeamsRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds the Teams message payload as indented JSON
+func (r msTeamsRenderer) Render(data *TopicData) string {
+	payload, err := json.MarshalIndent(newMSTeamsMessage(r.Card(data)), "", "  ")
+	if err != nil {
+		log.Printf("Error marshaling Teams message: %v", err)
+		return "{}"
+	}
+	return string(payload)
+}
+
+// Card builds the Adaptive Card for the next race and championship standings
+func (r msTeamsRenderer) Card(data *TopicData) adaptiveCard {
+	card := newAdaptiveCard()
+	emoji := emojiSet{flags: r.flags.or(unicodeFlags)}
+
+	if data.OffSeason {
+		var facts []cardFact
+		for _, fact := range offSeasonFacts(data, emoji) {
+			facts = append(facts, cardFact{Title: fact.Label, Value: fact.Value})
+		}
+		card.Body = append(card.Body,
+			cardElement{Type: "TextBlock", Text: fmt.Sprintf("F1 %d", data.Season), Size: "Large", Weight: "Bolder"},
+			cardElement{Type: "TextBlock", Text: fmt.Sprintf("The %d season is over", data.Season), IsSubtle: true},
+			cardElement{Type: "FactSet", Facts: facts},
+		)
+	} else if data.NextRaceErr != nil {
+		card.Body = append(card.Body,
+			cardElement{Type: "TextBlock", Text: fmt.Sprintf("F1 %d", data.Season), Size: "Large", Weight: "Bolder"},
+			cardElement{Type: "TextBlock", Text: "No upcoming races", IsSubtle: true},
+		)
+	} else {
+		race := data.NextRace
+		card.Body = append(card.Body,
+			cardElement{Type: "TextBlock", Text: fmt.Sprintf("%s %s", race.RaceName, emoji.raceFlag(race)), Size: "Large", Weight: "Bolder", Wrap: true},
+			cardElement{Type: "TextBlock", Text: fmt.Sprintf("Round %d · %s, %s", data.Round, race.Circuit.CircuitName, race.Country), IsSubtle: true, Wrap: true},
+		)
+
+		var facts []cardFact
+		if qualy := formatSessionTime(race.Schedule.Qualy); qualy != "" {
+			facts = append(facts, cardFact{Title: "Qualifying", Value: qualy})
+		}
+		if raceTime := formatSessionTime(race.Schedule.Race); raceTime != "" {
+			facts = append(facts, cardFact{Title: "Race", Value: raceTime})
+		}
+		if len(facts) > 0 {
+			card.Body = append(card.Body, cardElement{Type: "FactSet", Facts: facts})
+		}
+	}
+
+	if data.DriversErr == nil && len(data.Drivers) > 0 {
+		var facts []cardFact
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			facts = append(facts, cardFact{
+				Title: fmt.Sprintf("%d. %s %s", driver.Position, emoji.driverFlag(driver.Driver), driverAbbr(driver.Driver)),
+				Value: fmt.Sprintf("%s %s — %.0f", driver.Driver.Name, driver.Driver.Surname, driver.Points),
+			})
+		}
+		card.Body = append(card.Body,
+			cardElement{Type: "TextBlock", Text: "Drivers", Weight: "Bolder", Separator: true},
+			cardElement{Type: "FactSet", Facts: facts},
+		)
+	}
+
+	if data.TeamsErr == nil && len(data.Teams) > 0 {
+		var facts []cardFact
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+			facts = append(facts, cardFact{
+				Title: fmt.Sprintf("%d. %s", team.Position, teamAbbr(team)),
+				Value: fmt.Sprintf("%s — %.0f", team.Team.TeamName, team.Points),
+			})
+		}
+		card.Body = append(card.Body,
+			cardElement{Type: "TextBlock", Text: "Constructors", Weight: "Bolder", Separator: true},
+			cardElement{Type: "FactSet", Facts: facts},
+		)
+	}
+
+	return card
+}
+
+// msTeamsPublisher posts an Adaptive Card to a Teams incoming webhook
+type msTeamsPublisher struct {
+	renderer Renderer
+	url      string
+}
+
+// newMSTeamsPublisher creates a publisher for the webhook URL from opts or $TEAMS_WEBHOOK_URL
+func newMSTeamsPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	url := opts.WebhookURL
+	if url == "" {
+		url = os.Getenv("TEAMS_WEBHOOK_URL")
+	}
+	if url == "" {
+		return nil, errors.New("no webhook URL: set -webhook-url or TEAMS_WEBHOOK_URL")
+	}
+	return &msTeamsPublisher{renderer: renderer, url: url}, nil
+}
+
+// Publish sends the rendered card, wrapping plain text renderers' output in a single TextBlock
+func (p *msTeamsPublisher) Publish(data *TopicData) error {
+	var card adaptiveCard
+	if r, ok := p.renderer.(cardRenderer); ok {
+		card = r.Card(data)
+	} else {
+		text, err := renderText(p.renderer, data)
+		if err != nil {
+			return err
+		}
+		card = newAdaptiveCard()
+		card.Body = []cardElement{{Type: "TextBlock", Text: text, Wrap: true}}
+	}
+	return postWebhook(p.url, newMSTeamsMessage(card))
+}
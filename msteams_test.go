@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMSTeamsCard(t *testing.T) {
+	card := msTeamsRenderer{}.Card(testTopicData())
+
+	if card.Type != "AdaptiveCard" || len(card.Body) == 0 {
+		t.Fatalf("Unexpected card: %+v", card)
+	}
+	if got := card.Body[0].Text; got != "Lenovo Japanese Grand Prix 2025 🇯🇵" {
+		t.Errorf("Unexpected card title: %q", got)
+	}
+
+	var facts []cardFact
+	for _, element := range card.Body {
+		facts = append(facts, element.Facts...)
+	}
+	expected := []cardFact{
+		{Title: "Race", Value: "Sun April 6, 2025 at 05:00 UTC"},
+		{Title: "1. 🇬🇧 NOR", Value: "Lando Norris — 44"},
+		{Title: "1. MCL", Value: "McLaren Formula 1 Team — 78"},
+	}
+	for _, want := range expected {
+		found := false
+		for _, fact := range facts {
+			if fact == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Card should contain fact %+v, got %+v", want, facts)
+		}
+	}
+}
+
+func TestMSTeamsPublisher(t *testing.T) {
+	server, received := webhookRecorder(t, http.StatusOK)
+
+	publisher, err := newPublisher("teams", msTeamsRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var msg msTeamsMessage
+	if err := json.Unmarshal(*received, &msg); err != nil {
+		t.Fatalf("Webhook payload should be JSON: %v", err)
+	}
+	if msg.Type != "message" || len(msg.Attachments) != 1 {
+		t.Fatalf("Unexpected Teams message: %+v", msg)
+	}
+	if msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("Unexpected attachment content type: %q", msg.Attachments[0].ContentType)
+	}
+}
+
+func TestMSTeamsPublisherWrapsText(t *testing.T) {
+	server, received := webhookRecorder(t, http.StatusOK)
+
+	publisher, _ := newPublisher("teams", textRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var msg msTeamsMessage
+	json.Unmarshal(*received, &msg)
+	body := msg.Attachments[0].Content.Body
+	if len(body) != 1 || !strings.HasPrefix(body[0].Text, "F1 Data for 2025") {
+		t.Errorf("Expected text wrapped in a single TextBlock, got %+v", body)
+	}
+}
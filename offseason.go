@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// markOffSeason switches the data to the off-season layout once every race in the calendar has run,
+// fetching round 1 of the next season for the countdown
+func markOffSeason(data *TopicData, calendar *SeasonResponse) {
+	if calendar == nil {
+		return
+	}
+	if _, _, err := nextRaceAfter(calendar.Races, 0); err == nil {
+		return
+	}
+
+	data.OffSeason = true
+	data.NextRace, data.Round, data.NextRaceErr = nil, 0, nil
+
+	nextSeason, err := fetchCalendar(data.Season + 1)
+	if err != nil {
+		data.SeasonOpenerErr = err
+		log.Printf("Error fetching next season's calendar: %v", err)
+		return
+	}
+	data.SeasonOpener = &nextSeason.Races[0]
+}
+
+// preseasonTesting returns the configured start of pre-season testing, if it's still to come
+func preseasonTesting() (time.Time, bool) {
+	if config.PreseasonTesting == "" {
+		return time.Time{}, false
+	}
+	testing, err := time.Parse("2006-01-02", config.PreseasonTesting)
+	if err != nil {
+		warnOnce("invalid preseasonTesting date %q, expected YYYY-MM-DD", config.PreseasonTesting)
+		return time.Time{}, false
+	}
+	return testing, !testing.Before(today())
+}
+
+// today returns the start of the current day in UTC
+func today() time.Time {
+	return now().UTC().Truncate(24 * time.Hour)
+}
+
+// countdown describes how long until a date, e.g. "in 12 days"
+func countdown(date time.Time) string {
+	switch days := int(date.Sub(today()).Hours() / 24); {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+// champions returns the drivers' and constructors' champions from final standings, or nil if unknown
+func champions(data *TopicData) (*DriverStanding, *TeamStanding) {
+	var driver *DriverStanding
+	var team *TeamStanding
+	if data.DriversErr == nil && len(data.Drivers) > 0 {
+		driver = &data.Drivers[0]
+	}
+	if data.TeamsErr == nil && len(data.Teams) > 0 {
+		team = &data.Teams[0]
+	}
+	return driver, team
+}
+
+// renderOffSeason builds the Slack topic between the final race and the next season, e.g.
+// ":f1: 2025 Champions: :f1ln:NOR :gb: & :m1::f1tl:MCL // Next: Testing in 40 days, 2026 R1 Australia :flag-au: (Mar 6-8)"
+func (r slackRenderer) renderOffSeason(data *TopicData, emoji emojiSet) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %d Champions: ", emoji.f1(), data.Season))
+
+	driver, team := champions(data)
+	var winners []string
+	if driver != nil {
+		winners = append(winners, fmt.Sprintf("%s%s %s", emoji.driver(driver.DriverID), driverAbbr(driver.Driver), emoji.driverFlag(driver.Driver)))
+	}
+	if team != nil {
+		winners = append(winners, emoji.team(team.TeamID)+teamAbbr(*team))
+	}
+	if len(winners) == 0 {
+		winners = append(winners, "No data")
+	}
+	sb.WriteString(strings.Join(winners, " & "))
+
+	sb.WriteString(" // Next: ")
+	if testing, ok := preseasonTesting(); ok {
+		sb.WriteString(fmt.Sprintf("Testing %s, ", countdown(testing)))
+	}
+	if opener := data.SeasonOpener; opener != nil {
+		sb.WriteString(fmt.Sprintf("%d R1 %s %s", data.Season+1, shortRaceName(opener), emoji.raceFlag(opener)))
+		if raceDate, err := time.Parse("2006-01-02", opener.Schedule.Race.Date); err == nil {
+			weekendStart := raceDate.AddDate(0, 0, -2)
+			sb.WriteString(fmt.Sprintf(" (%s %d-%d) %s", weekendStart.Format("Jan"), weekendStart.Day(), raceDate.Day(), countdown(raceDate)))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("%d season TBC", data.Season+1))
+	}
+
+	sb.WriteString(fantasySegment(data))
+	return sb.String()
+}
+
+// renderOffSeason builds the detailed text between the final race and the next season
+func (r textRenderer) renderOffSeason(data *TopicData, emoji emojiSet) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("F1 Data for %d\n\n", data.Season))
+	sb.WriteString(fmt.Sprintf("The %d season is over\n", data.Season))
+
+	driver, team := champions(data)
+	if driver != nil {
+		sb.WriteString(fmt.Sprintf("Drivers' Champion: %s %s %s (%s) - %.1f points\n",
+			emoji.driverFlag(driver.Driver), driver.Driver.Name, driver.Driver.Surname, driver.Team.TeamName, driver.Points))
+	}
+	if team != nil {
+		sb.WriteString(fmt.Sprintf("Constructors' Champion: %s - %.1f points\n", team.Team.TeamName, team.Points))
+	}
+	sb.WriteString("\n")
+
+	if testing, ok := preseasonTesting(); ok {
+		sb.WriteString(fmt.Sprintf("Pre-season testing: %s (%s)\n", testing.Format("January 2, 2006"), countdown(testing)))
+	}
+	if opener := data.SeasonOpener; opener != nil {
+		sb.WriteString(fmt.Sprintf("Season Opener: %s (Round 1)\n", opener.RaceName))
+		if raceDate, err := time.Parse("2006-01-02", opener.Schedule.Race.Date); err == nil {
+			sb.WriteString(fmt.Sprintf("Date: %s (%s)\n", raceDate.Format("January 2, 2006"), countdown(raceDate)))
+		}
+		sb.WriteString(fmt.Sprintf("Country: %s %s\n", opener.Country, emoji.raceFlag(opener)))
+	} else if data.SeasonOpenerErr != nil {
+		sb.WriteString(fmt.Sprintf("Season opener: %v\n", data.SeasonOpenerErr))
+	}
+
+	return sb.String()
+}
+
+// offSeasonFact is a labelled line of the off-season summary, for renderers that lay out tables or fields
+type offSeasonFact struct {
+	Label string
+	Value string
+}
+
+// offSeasonFacts returns the champions, testing date and season opener for the off-season layouts
+func offSeasonFacts(data *TopicData, emoji emojiSet) []offSeasonFact {
+	var facts []offSeasonFact
+
+	driver, team := champions(data)
+	if driver != nil {
+		facts = append(facts, offSeasonFact{"Drivers' Champion", fmt.Sprintf("%s %s %s (%s)",
+			emoji.driverFlag(driver.Driver), driver.Driver.Name, driver.Driver.Surname, driver.Team.TeamName)})
+	}
+	if team != nil {
+		facts = append(facts, offSeasonFact{"Constructors' Champion", team.Team.TeamName})
+	}
+
+	if testing, ok := preseasonTesting(); ok {
+		facts = append(facts, offSeasonFact{"Pre-season testing", fmt.Sprintf("%s (%s)", testing.Format("January 2, 2006"), countdown(testing))})
+	}
+	switch opener := data.SeasonOpener; {
+	case opener != nil:
+		value := fmt.Sprintf("%s %s", opener.RaceName, emoji.raceFlag(opener))
+		if raceDate, err := time.Parse("2006-01-02", opener.Schedule.Race.Date); err == nil {
+			value += fmt.Sprintf(", %s (%s)", raceDate.Format("January 2, 2006"), countdown(raceDate))
+		}
+		facts = append(facts, offSeasonFact{"Season opener", value})
+	case data.SeasonOpenerErr != nil:
+		facts = append(facts, offSeasonFact{"Season opener", data.SeasonOpenerErr.Error()})
+	default:
+		facts = append(facts, offSeasonFact{"Season opener", fmt.Sprintf("%d season TBC", data.Season+1)})
+	}
+	return facts
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// offSeasonData returns the test data as it would be after the final race
+func offSeasonData() *TopicData {
+	data := testTopicData()
+	data.OffSeason = true
+	data.NextRace, data.Round = nil, 0
+	data.SeasonOpener = &Race{
+		RaceID:   "australian_2026",
+		RaceName: "Formula 1 Louis Vuitton Australian Grand Prix 2026",
+		Schedule: Schedule{Race: TimeInfo{Date: "2026-03-08", Time: "04:00:00Z"}},
+		Circuit:  Circuit{CircuitID: "albert_park"},
+		Country:  "Australia",
+	}
+	return data
+}
+
+// fixClock sets now to a fixed time for the rest of the test
+func fixClock(t *testing.T, fixed time.Time) {
+	t.Helper()
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestSlackRendererOffSeason(t *testing.T) {
+	fixClock(t, time.Date(2025, 12, 20, 9, 0, 0, 0, time.UTC))
+
+	expected := ":f1: 2025 Champions: :f1ln:NOR :gb: & :m1::f1tl:MCL // " +
+		"Next: 2026 R1 Australia :flag-au: (Mar 6-8) in 78 days // Fantasy: `thanksai`"
+	if output := (slackRenderer{}).Render(offSeasonData()); output != expected {
+		t.Errorf("Unexpected off-season topic:\n got: %s\nwant: %s", output, expected)
+	}
+}
+
+func TestSlackRendererOffSeasonTesting(t *testing.T) {
+	fixClock(t, time.Date(2025, 12, 20, 9, 0, 0, 0, time.UTC))
+	defer func() { config = Config{} }()
+	config.PreseasonTesting = "2026-02-26"
+
+	output := slackRenderer{}.Render(offSeasonData())
+	if !strings.Contains(output, "Next: Testing in 68 days, 2026 R1 Australia") {
+		t.Errorf("Topic should count down to testing, got %s", output)
+	}
+
+	// Once testing has started only the opener is counted down to
+	fixClock(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	output = slackRenderer{}.Render(offSeasonData())
+	if strings.Contains(output, "Testing") || !strings.Contains(output, "(Mar 6-8) in 7 days") {
+		t.Errorf("Topic should only count down to the opener, got %s", output)
+	}
+}
+
+func TestSlackRendererOffSeasonUnknownOpener(t *testing.T) {
+	data := offSeasonData()
+	data.SeasonOpener = nil
+
+	if output := (slackRenderer{}).Render(data); !strings.Contains(output, "Next: 2026 season TBC") {
+		t.Errorf("Topic should say the next season is TBC, got %s", output)
+	}
+}
+
+func TestTextRendererOffSeason(t *testing.T) {
+	fixClock(t, time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC))
+
+	output := textRenderer{}.Render(offSeasonData())
+	expected := []string{
+		"The 2025 season is over",
+		"Drivers' Champion: 🇬🇧 Lando Norris (McLaren Formula 1 Team) - 44.0 points",
+		"Constructors' Champion: McLaren Formula 1 Team - 78.0 points",
+		"Season Opener: Formula 1 Louis Vuitton Australian Grand Prix 2026 (Round 1)",
+		"Date: March 8, 2026 (tomorrow)",
+		"Country: Australia 🇦🇺",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestFetchTopicDataOffSeason(t *testing.T) {
+	fixClock(t, time.Date(2025, 12, 20, 9, 0, 0, 0, time.UTC))
+	newFakeF1API(t, map[string]string{
+		"/current":                           `{"season": 2025, "races": [{"raceId": "abu_dhabi_2025", "schedule": {"race": {"date": "2025-12-07"}}}]}`,
+		"/current/next":                      `{"message": "No upcoming races", "status": 404}`,
+		"/2026":                              `{"season": 2026, "races": [{"raceId": "australian_2026", "schedule": {"race": {"date": "2026-03-08"}}, "circuit": {"circuitId": "albert_park"}}]}`,
+		"/current/drivers-championship":      `{"season": 2025, "drivers_championship": [{"driverId": "norris", "points": 423, "position": 1}]}`,
+		"/current/constructors-championship": `{"season": 2025, "constructors_championship": [{"teamId": "mclaren", "points": 833, "position": 1}]}`,
+	})
+
+	data := fetchTopicData()
+	if !data.OffSeason || data.NextRaceErr != nil {
+		t.Errorf("Expected the off-season once the calendar is exhausted, got %+v", data)
+	}
+	if data.SeasonOpener == nil || data.SeasonOpener.RaceID != "australian_2026" {
+		t.Errorf("Expected the 2026 opener, got %+v (%v)", data.SeasonOpener, data.SeasonOpenerErr)
+	}
+}
+
+func TestAllRenderersOffSeason(t *testing.T) {
+	fixClock(t, time.Date(2025, 12, 20, 9, 0, 0, 0, time.UTC))
+
+	// Every renderer should show the champions and the opener rather than the cleared next race
+	tests := map[string][]string{
+		"text":     {"Drivers' Champion: 🇬🇧 Lando Norris", "Season Opener: Formula 1 Louis Vuitton Australian Grand Prix 2026"},
+		"slack":    {"Champions: :f1ln:NOR", "2026 R1 Australia"},
+		"markdown": {"## Off-Season", "| **Drivers' Champion** | 🇬🇧 Lando Norris (McLaren Formula 1 Team) |", "March 8, 2026 (in 78 days)"},
+		"html":     {"<h2>Off-Season</h2>", "<tr><th>Constructors&#39; Champion</th><td>McLaren Formula 1 Team</td></tr>"},
+		"blocks":   {"The 2025 season is over", `*Drivers' Champion*\n:gb: Lando Norris`},
+		"discord":  {"**The 2025 season is over**", "Season opener: Formula 1 Louis Vuitton Australian Grand Prix 2026 🇦🇺"},
+		"teams":    {"The 2025 season is over", `"title": "Constructors' Champion"`, `"value": "McLaren Formula 1 Team"`},
+	}
+	for name, renderer := range renderers {
+		want, ok := tests[name]
+		if !ok {
+			t.Errorf("No off-season expectations for the %s renderer", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			output := renderer.Render(offSeasonData())
+			for _, w := range want {
+				if !strings.Contains(output, w) {
+					t.Errorf("Output should contain %q, got:\n%s", w, output)
+				}
+			}
+		})
+	}
+}
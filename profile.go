@@ -0,0 +1,231 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// nameMatchers are the ways a lowercased name can match a lowercased query, best first
+var nameMatchers = []func(name, query string) bool{
+	func(name, query string) bool { return name == query },
+	strings.HasPrefix,
+	strings.Contains,
+}
+
+// matchNames finds the items whose names best match a query, ignoring case, trying each matcher in turn
+func matchNames[T any](items []T, names func(T) []string, query string, matchers []func(name, query string) bool) []T {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	for _, match := range matchers {
+		var matches []T
+		for _, item := range items {
+			for _, name := range names(item) {
+				if match(strings.ToLower(name), query) {
+					matches = append(matches, item)
+					break
+				}
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// fuzzyMatch finds the items whose names best match a query, ignoring case: exact matches win over
+// prefix matches, which win over matches anywhere in a name
+func fuzzyMatch[T any](items []T, names func(T) []string, query string) []T {
+	return matchNames(items, names, query, nameMatchers)
+}
+
+// exactMatch finds the items with a name equal to the query, ignoring case, for picking names out of
+// free text where partial matches would be noise
+func exactMatch[T any](items []T, names func(T) []string, query string) []T {
+	return matchNames(items, names, query, nameMatchers[:1])
+}
+
+// driverNames returns the names a driver can be looked up by
+func driverNames(driver DriverStanding) []string {
+	return []string{driver.DriverID, driver.Driver.Name, driver.Driver.Surname, driver.Driver.ShortName,
+		driver.Driver.Name + " " + driver.Driver.Surname}
+}
+
+// matchDriver finds the one driver in the standings matching a query such as "ver", "max" or "norris"
+func matchDriver(drivers []DriverStanding, query string) (DriverStanding, error) {
+	matches := fuzzyMatch(drivers, driverNames, query)
+
+	switch len(matches) {
+	case 0:
+		return DriverStanding{}, fmt.Errorf("no driver matching %q", query)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, driver := range matches {
+		names = append(names, driver.Driver.Name+" "+driver.Driver.Surname)
+	}
+	return DriverStanding{}, fmt.Errorf("%q matches %s, be more specific", query, strings.Join(names, ", "))
+}
+
+// matchTeam finds the one team in the standings matching a query such as "ferrari", "rbr" or "red bull"
+func matchTeam(teams []TeamStanding, query string) (TeamStanding, error) {
+	matches := fuzzyMatch(teams, func(team TeamStanding) []string {
+		return []string{team.TeamID, strings.ReplaceAll(team.TeamID, "_", " "), teamAbbr(team), team.Team.TeamName}
+	}, query)
+
+	switch len(matches) {
+	case 0:
+		return TeamStanding{}, fmt.Errorf("no team matching %q", query)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, team := range matches {
+		names = append(names, team.Team.TeamName)
+	}
+	return TeamStanding{}, fmt.Errorf("%q matches %s, be more specific", query, strings.Join(names, ", "))
+}
+
+// racesRun counts the races of the season that have started, for points per race
+func racesRun(data *TopicData) int {
+	if snapshot.AsOfRound > 0 {
+		return snapshot.AsOfRound
+	}
+	if len(data.Calendar) == 0 {
+		return max(data.Round-1, 0)
+	}
+
+	run := 0
+	for _, race := range data.Calendar {
+		if start, _, err := parseSessionTime(race.Schedule.Race); err == nil && start.Before(now()) {
+			run++
+		}
+	}
+	return run
+}
+
+// teammates returns the other drivers in the standings for a team
+func teammates(drivers []DriverStanding, teamID, driverID string) []DriverStanding {
+	var mates []DriverStanding
+	for _, driver := range drivers {
+		if driver.TeamID == teamID && driver.DriverID != driverID {
+			mates = append(mates, driver)
+		}
+	}
+	return mates
+}
+
+// pointsLine formats points with the average per race run, when any have been
+func pointsLine(points float64, races int) string {
+	if races == 0 {
+		return fmt.Sprintf("%g", points)
+	}
+	return fmt.Sprintf("%g (%.1f per race)", points, points/float64(races))
+}
+
+// gapText describes a points gap to another driver, e.g. "+8 on Piastri"
+func gapText(points float64, other DriverStanding) string {
+	return fmt.Sprintf("%+g on %s", points-other.Points, other.Driver.Surname)
+}
+
+// driverProfile describes a driver's biography and season so far
+func driverProfile(data *TopicData, driver DriverStanding, emoji emojiSet) string {
+	var sb strings.Builder
+
+	number := ""
+	if driver.Driver.Number > 0 {
+		number = fmt.Sprintf(" #%d", driver.Driver.Number)
+	}
+	sb.WriteString(fmt.Sprintf("%s %s %s%s (%s)\n", emoji.driverFlag(driver.Driver), driver.Driver.Name, driver.Driver.Surname, number, driverAbbr(driver.Driver)))
+	sb.WriteString(fmt.Sprintf("Team: %s\n", driver.Team.TeamName))
+	if driver.Driver.Nationality != "" {
+		sb.WriteString(fmt.Sprintf("Nationality: %s\n", driver.Driver.Nationality))
+	}
+	if driver.Driver.Birthday != "" {
+		sb.WriteString(fmt.Sprintf("Birthday: %s\n", driver.Driver.Birthday))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%d season:\n", data.Season))
+	sb.WriteString(fmt.Sprintf("  Position: P%d\n", driver.Position))
+	sb.WriteString(fmt.Sprintf("  Points: %s\n", pointsLine(driver.Points, racesRun(data))))
+	sb.WriteString(fmt.Sprintf("  Wins: %d\n", driver.Wins))
+	for _, mate := range teammates(data.Drivers, driver.TeamID, driver.DriverID) {
+		sb.WriteString(fmt.Sprintf("  Teammate: %s\n", gapText(driver.Points, mate)))
+	}
+	return sb.String()
+}
+
+// teamProfile describes a team's history and season so far
+func teamProfile(data *TopicData, team TeamStanding) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s (%s)\n", team.Team.TeamName, teamAbbr(team)))
+	if team.Team.Country != "" {
+		sb.WriteString(fmt.Sprintf("Country: %s\n", team.Team.Country))
+	}
+	if team.Team.FirstAppearance > 0 {
+		sb.WriteString(fmt.Sprintf("First appearance: %d\n", team.Team.FirstAppearance))
+	}
+	sb.WriteString(fmt.Sprintf("Championships: %d constructors', %d drivers'\n", team.Team.ConstructorsChampionships, team.Team.DriversChampionships))
+
+	sb.WriteString(fmt.Sprintf("\n%d season:\n", data.Season))
+	sb.WriteString(fmt.Sprintf("  Position: P%d\n", team.Position))
+	sb.WriteString(fmt.Sprintf("  Points: %s\n", pointsLine(team.Points, racesRun(data))))
+	sb.WriteString(fmt.Sprintf("  Wins: %d\n", team.Wins))
+
+	drivers := teammates(data.Drivers, team.TeamID, "")
+	for _, driver := range drivers {
+		sb.WriteString(fmt.Sprintf("  %s %s: P%d, %g points\n", driver.Driver.Name, driver.Driver.Surname, driver.Position, driver.Points))
+	}
+	if len(drivers) == 2 {
+		sb.WriteString(fmt.Sprintf("  Gap: %s\n", gapText(drivers[0].Points, drivers[1])))
+	}
+	return sb.String()
+}
+
+// driverCommand prints the profile of the driver matching the arguments
+func driverCommand(args []string) error {
+	fs := flag.NewFlagSet("driver", flag.ExitOnError)
+	fs.Parse(args)
+	query := strings.Join(fs.Args(), " ")
+	if query == "" {
+		return fmt.Errorf("usage: driver <id or name>")
+	}
+
+	data := fetchTopicData()
+	if data.DriversErr != nil {
+		return data.DriversErr
+	}
+	driver, err := matchDriver(data.Drivers, query)
+	if err != nil {
+		return err
+	}
+	fmt.Print(driverProfile(data, driver, emojiSet{flags: unicodeFlags}))
+	return nil
+}
+
+// teamCommand prints the profile of the team matching the arguments
+func teamCommand(args []string) error {
+	fs := flag.NewFlagSet("team", flag.ExitOnError)
+	fs.Parse(args)
+	query := strings.Join(fs.Args(), " ")
+	if query == "" {
+		return fmt.Errorf("usage: team <id or name>")
+	}
+
+	data := fetchTopicData()
+	if data.TeamsErr != nil {
+		return data.TeamsErr
+	}
+	team, err := matchTeam(data.Teams, query)
+	if err != nil {
+		return err
+	}
+	fmt.Print(teamProfile(data, team))
+	return nil
+}
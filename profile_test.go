@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// profileData adds teammates and biographies to the renderer fixture
+func profileData() *TopicData {
+	data := testTopicData()
+	data.Drivers[0].Driver.Number = 4
+	data.Drivers[0].Driver.Birthday = "1999-11-13"
+	data.Drivers = append(data.Drivers,
+		DriverStanding{DriverID: "piastri", TeamID: "mclaren", Points: 34, Position: 4,
+			Driver: Driver{Name: "Oscar", Surname: "Piastri", Nationality: "Australia", ShortName: "PIA"},
+			Team:   Team{TeamName: "McLaren Formula 1 Team"}},
+		DriverStanding{DriverID: "hamilton", TeamID: "ferrari", Points: 9, Position: 10,
+			Driver: Driver{Name: "Lewis", Surname: "Hamilton", Nationality: "Great Britain", ShortName: "HAM"},
+			Team:   Team{TeamName: "Scuderia Ferrari HP"}},
+	)
+	data.Teams[0].Team.Country = "Great Britain"
+	data.Teams[0].Team.FirstAppearance = 1966
+	data.Teams[0].Team.ConstructorsChampionships = 9
+	data.Teams[0].Team.DriversChampionships = 12
+	return data
+}
+
+func TestMatchDriver(t *testing.T) {
+	drivers := profileData().Drivers
+
+	tests := map[string]string{
+		"ver":            "max_verstappen",
+		"max":            "max_verstappen",
+		"NOR":            "norris",
+		"lando norris":   "norris",
+		"pias":           "piastri",
+		"stappen":        "max_verstappen",
+		"max_verstappen": "max_verstappen",
+	}
+	for query, want := range tests {
+		driver, err := matchDriver(drivers, query)
+		if err != nil || driver.DriverID != want {
+			t.Errorf("matchDriver(%q) = %s, %v, want %s", query, driver.DriverID, err, want)
+		}
+	}
+
+	if _, err := matchDriver(drivers, "ri"); err == nil || !strings.Contains(err.Error(), "be more specific") {
+		t.Errorf("Expected an ambiguous match error, got %v", err)
+	}
+	if _, err := matchDriver(drivers, "senna"); err == nil || err.Error() != `no driver matching "senna"` {
+		t.Errorf("Expected no match, got %v", err)
+	}
+}
+
+func TestMatchTeam(t *testing.T) {
+	teams := profileData().Teams
+
+	tests := map[string]string{
+		"mclaren":  "mclaren",
+		"red bull": "red_bull",
+		"rbr":      "red_bull",
+		"merc":     "mercedes",
+	}
+	for query, want := range tests {
+		team, err := matchTeam(teams, query)
+		if err != nil || team.TeamID != want {
+			t.Errorf("matchTeam(%q) = %s, %v, want %s", query, team.TeamID, err, want)
+		}
+	}
+
+	if _, err := matchTeam(teams, "formula 1 team"); err == nil || !strings.Contains(err.Error(), "be more specific") {
+		t.Errorf("Expected an ambiguous match error, got %v", err)
+	}
+}
+
+func TestDriverProfile(t *testing.T) {
+	data := profileData()
+
+	output := driverProfile(data, data.Drivers[0], emojiSet{flags: unicodeFlags})
+	expected := "🇬🇧 Lando Norris #4 (NOR)\n" +
+		"Team: McLaren Formula 1 Team\n" +
+		"Nationality: Great Britain\n" +
+		"Birthday: 1999-11-13\n" +
+		"\n2025 season:\n" +
+		"  Position: P1\n" +
+		"  Points: 44 (22.0 per race)\n" +
+		"  Wins: 1\n" +
+		"  Teammate: +10 on Piastri\n"
+	if output != expected {
+		t.Errorf("Unexpected profile:\n%s\nwant:\n%s", output, expected)
+	}
+}
+
+func TestTeamProfile(t *testing.T) {
+	data := profileData()
+
+	output := teamProfile(data, data.Teams[0])
+	expected := "McLaren Formula 1 Team (MCL)\n" +
+		"Country: Great Britain\n" +
+		"First appearance: 1966\n" +
+		"Championships: 9 constructors', 12 drivers'\n" +
+		"\n2025 season:\n" +
+		"  Position: P1\n" +
+		"  Points: 78 (39.0 per race)\n" +
+		"  Wins: 2\n" +
+		"  Lando Norris: P1, 44 points\n" +
+		"  Oscar Piastri: P4, 34 points\n" +
+		"  Gap: +10 on Piastri\n"
+	if output != expected {
+		t.Errorf("Unexpected profile:\n%s\nwant:\n%s", output, expected)
+	}
+}
+
+func TestRacesRun(t *testing.T) {
+	fixClock(t, time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC))
+
+	data := testTopicData()
+	data.Calendar = []Race{
+		{Schedule: Schedule{Race: TimeInfo{Date: "2025-03-16", Time: "04:00:00Z"}}},
+		{Schedule: Schedule{Race: TimeInfo{Date: "2025-03-23", Time: "07:00:00Z"}}},
+		{Schedule: Schedule{Race: TimeInfo{Date: "2025-04-06", Time: "05:00:00Z"}}},
+		{Schedule: Schedule{Race: TimeInfo{Date: "2025-04-13", Time: "15:00:00Z"}}},
+	}
+	if got := racesRun(data); got != 3 {
+		t.Errorf("Expected 3 races run, got %d", got)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Publisher sends rendered F1 data to a destination
+type Publisher interface {
+	Publish(data *TopicData) error
+}
+
+// messageRenderer is implemented by renderers that can produce a Slack message with blocks
+type messageRenderer interface {
+	Message(data *TopicData) slackMessage
+}
+
+// PublishOptions holds the settings publishers need to reach their destination
+type PublishOptions struct {
+	WebhookURL string
+	Channel    string
+	Token      string
+	Out        io.Writer
+}
+
+// publishOpts holds the -webhook-url, -channel and -token flags, shared by the publish targets and the post command
+var publishOpts PublishOptions
+
+// addSlackFlags registers -channel and -token on a flag set, storing them in publishOpts so the global flags
+// and a subcommand's are the same settings
+func addSlackFlags(fs *flag.FlagSet) {
+	fs.StringVar(&publishOpts.Channel, "channel", publishOpts.Channel, "Slack channel ID for -publish api and topic and the post command")
+	fs.StringVar(&publishOpts.Token, "token", publishOpts.Token, "Slack token for -publish api and topic and the post command (defaults to $SLACK_TOKEN)")
+}
+
+// Map publish target names to constructors for their publishers
+var publishers = map[string]func(renderer Renderer, opts PublishOptions) (Publisher, error){
+	"stdout":  newStdoutPublisher,
+	"webhook": newWebhookPublisher,
+	"api":     newAPIPublisher,
+	"topic":   newTopicPublisher,
+	"discord": newDiscordPublisher,
+	"teams":   newMSTeamsPublisher,
+}
+
+// Map publish targets to the format they use when none is given, so each target gets emoji it can display
+var publisherFormats = map[string]string{
+	"topic":   "slack",
+	"discord": "discord",
+	"teams":   "teams",
+}
+
+// defaultFormat picks the format when -format isn't given: -slack is shorthand for slack, then the publish
+// target's own format, and detailed text is the default
+func defaultFormat(slack bool, target string) string {
+	if slack {
+		return "slack"
+	}
+	if format, ok := publisherFormats[target]; ok {
+		return format
+	}
+	return "text"
+}
+
+// publisherNames returns the sorted list of available publish targets
+func publisherNames() []string {
+	names := make([]string, 0, len(publishers))
+	for name := range publishers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newPublisher creates the publisher for a target name
+func newPublisher(target string, renderer Renderer, opts PublishOptions) (Publisher, error) {
+	constructor, ok := publishers[target]
+	if !ok {
+		return nil, fmt.Errorf("unknown publish target %q, expected one of: %s", target, strings.Join(publisherNames(), ", "))
+	}
+	return constructor(renderer, opts)
+}
+
+// renderText renders data as text, returning an error if the renderer reported one
+func renderText(renderer Renderer, data *TopicData) (string, error) {
+	output := renderer.Render(data)
+	if strings.HasPrefix(output, "ERROR:") {
+		return output, errors.New(strings.TrimPrefix(output, "ERROR: "))
+	}
+	return output, nil
+}
+
+// renderSlackMessage renders data as a Slack message, using blocks when the renderer supports them
+func renderSlackMessage(renderer Renderer, data *TopicData) (slackMessage, error) {
+	if r, ok := renderer.(messageRenderer); ok {
+		return r.Message(data), nil
+	}
+	text, err := renderText(renderer, data)
+	return slackMessage{Text: text}, err
+}
+
+// stdoutPublisher writes rendered output to stdout
+type stdoutPublisher struct {
+	renderer Renderer
+	out      io.Writer
+}
+
+// newStdoutPublisher creates a publisher that prints to opts.Out, or stdout if unset
+func newStdoutPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	return &stdoutPublisher{renderer: renderer, out: out}, nil
+}
+
+// Publish prints the rendered output, even if the renderer reported an error
+func (p *stdoutPublisher) Publish(data *TopicData) error {
+	output, err := renderText(p.renderer, data)
+	fmt.Fprintln(p.out, output)
+	return err
+}
+
+// webhookPublisher posts to a Slack incoming webhook
+type webhookPublisher struct {
+	renderer Renderer
+	url      string
+}
+
+// newWebhookPublisher creates a publisher for the webhook URL from opts or $SLACK_WEBHOOK_URL
+func newWebhookPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	url := opts.WebhookURL
+	if url == "" {
+		url = os.Getenv("SLACK_WEBHOOK_URL")
+	}
+	if url == "" {
+		return nil, errors.New("no webhook URL: set -webhook-url or SLACK_WEBHOOK_URL")
+	}
+	return &webhookPublisher{renderer: renderer, url: url}, nil
+}
+
+// Publish sends the rendered text or Block Kit message to the webhook
+func (p *webhookPublisher) Publish(data *TopicData) error {
+	msg, err := renderSlackMessage(p.renderer, data)
+	if err != nil {
+		return err
+	}
+	return postWebhook(p.url, msg)
+}
+
+// postWebhook POSTs a JSON payload to an incoming webhook URL, treating any non-2xx status as an error
+func postWebhook(url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook payload: %v", err)
+	}
+
+	log.Printf("Posting to webhook")
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error posting to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading webhook response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// apiPublisher posts a message to a channel with chat.postMessage
+type apiPublisher struct {
+	renderer Renderer
+	client   *slackClient
+	channel  string
+}
+
+// newAPIPublisher creates a publisher that posts with the token from opts or $SLACK_TOKEN
+func newAPIPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	client, err := channelClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &apiPublisher{renderer: renderer, client: client, channel: opts.Channel}, nil
+}
+
+// Publish posts the rendered text or Block Kit message to the channel
+func (p *apiPublisher) Publish(data *TopicData) error {
+	msg, err := renderSlackMessage(p.renderer, data)
+	if err != nil {
+		return err
+	}
+	if err := p.client.PostMessage(p.channel, msg); err != nil {
+		return err
+	}
+	log.Printf("Posted F1 message to %s", p.channel)
+	return nil
+}
+
+// topicPublisher sets a channel's topic with conversations.setTopic, which needs the channels:manage scope
+type topicPublisher struct {
+	renderer Renderer
+	client   *slackClient
+	channel  string
+}
+
+// newTopicPublisher creates a publisher that sets the topic with the token from opts or $SLACK_TOKEN
+func newTopicPublisher(renderer Renderer, opts PublishOptions) (Publisher, error) {
+	client, err := channelClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &topicPublisher{renderer: renderer, client: client, channel: opts.Channel}, nil
+}
+
+// Publish sets the rendered text as the channel topic
+func (p *topicPublisher) Publish(data *TopicData) error {
+	topic, err := renderText(p.renderer, data)
+	if err != nil {
+		return err
+	}
+	if err := p.client.SetTopic(p.channel, topic); err != nil {
+		return err
+	}
+	log.Printf("Set topic for %s", p.channel)
+	return nil
+}
+
+// channelClient validates the channel and token in opts and returns a Slack client for them
+func channelClient(opts PublishOptions) (*slackClient, error) {
+	if opts.Channel == "" {
+		return nil, errors.New("-channel is required")
+	}
+	token, err := slackToken(opts.Token)
+	if err != nil {
+		return nil, err
+	}
+	return newSlackClient(token), nil
+}
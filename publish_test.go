@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStdoutPublisher(t *testing.T) {
+	var out bytes.Buffer
+	publisher, err := newPublisher("stdout", slackRenderer{}, PublishOptions{Out: &out})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), ":f1: 2025 Next: R3/24 Japan") {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestStdoutPublisherTopicTooLong(t *testing.T) {
+	data := testTopicData()
+	data.NextRace.Circuit.CircuitID = "unknown"
+	data.NextRace.RaceName = strings.Repeat("Very Long ", 30) + "Grand Prix 2025"
+
+	var out bytes.Buffer
+	publisher, _ := newPublisher("stdout", slackRenderer{}, PublishOptions{Out: &out})
+	if err := publisher.Publish(data); err == nil {
+		t.Error("Expected an error for a topic over the character limit")
+	}
+	if !strings.HasPrefix(out.String(), "ERROR: Slack topic exceeds 250 character limit") {
+		t.Errorf("The error should still be printed, got %q", out.String())
+	}
+}
+
+// webhookRecorder starts an httptest server that records webhook payloads and replies with status
+func webhookRecorder(t *testing.T, status int) (*httptest.Server, *[]byte) {
+	t.Helper()
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			io.WriteString(w, "ok")
+		} else {
+			io.WriteString(w, "invalid_payload")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestWebhookPublisherText(t *testing.T) {
+	server, received := webhookRecorder(t, http.StatusOK)
+
+	publisher, err := newPublisher("webhook", slackRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(*received, &msg); err != nil {
+		t.Fatalf("Webhook payload should be JSON: %v", err)
+	}
+	if !strings.HasPrefix(msg.Text, ":f1: 2025") || len(msg.Blocks) != 0 {
+		t.Errorf("Expected a text-only payload, got %+v", msg)
+	}
+}
+
+func TestWebhookPublisherBlocks(t *testing.T) {
+	server, received := webhookRecorder(t, http.StatusOK)
+
+	publisher, _ := newPublisher("webhook", blockKitRenderer{}, PublishOptions{WebhookURL: server.URL})
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(*received, &msg); err != nil {
+		t.Fatalf("Webhook payload should be JSON: %v", err)
+	}
+	if len(msg.Blocks) == 0 || msg.Blocks[0].Type != "header" {
+		t.Errorf("Expected a Block Kit payload, got %+v", msg)
+	}
+}
+
+func TestWebhookPublisherError(t *testing.T) {
+	server, _ := webhookRecorder(t, http.StatusBadRequest)
+
+	publisher, _ := newPublisher("webhook", slackRenderer{}, PublishOptions{WebhookURL: server.URL})
+	err := publisher.Publish(testTopicData())
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Errorf("Expected webhook error, got %v", err)
+	}
+}
+
+func TestWebhookPublisherRequiresURL(t *testing.T) {
+	t.Setenv("SLACK_WEBHOOK_URL", "")
+	if _, err := newPublisher("webhook", slackRenderer{}, PublishOptions{}); err == nil {
+		t.Error("Expected an error without a webhook URL")
+	}
+}
+
+func TestAPIPublisher(t *testing.T) {
+	fake := newFakeSlack(t)
+
+	publisher, err := newPublisher("api", blockKitRenderer{}, PublishOptions{Channel: "C123", Token: "xoxb-test"})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &msg); err != nil {
+		t.Fatalf("chat.postMessage body should be JSON: %v", err)
+	}
+	if msg.Channel != "C123" || len(msg.Blocks) == 0 {
+		t.Errorf("Unexpected chat.postMessage payload: %+v", msg)
+	}
+}
+
+func TestTopicPublisher(t *testing.T) {
+	fake := newFakeSlack(t)
+
+	publisher, err := newPublisher("topic", slackRenderer{}, PublishOptions{Channel: "C123", Token: "xoxb-test"})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var req map[string]string
+	if err := json.Unmarshal(fake.requests["conversations.setTopic"], &req); err != nil {
+		t.Fatalf("conversations.setTopic body should be JSON: %v", err)
+	}
+	if req["channel"] != "C123" || !strings.HasPrefix(req["topic"], ":f1: 2025") {
+		t.Errorf("Unexpected conversations.setTopic payload: %+v", req)
+	}
+}
+
+func TestTopicPublisherDefaultFormat(t *testing.T) {
+	fake := newFakeSlack(t)
+
+	format := defaultFormat(false, "topic")
+	if format != "slack" {
+		t.Fatalf("Expected -publish topic to default to the slack format, got %q", format)
+	}
+	publisher, err := newPublisher("topic", renderers[format], PublishOptions{Channel: "C123", Token: "xoxb-test"})
+	if err != nil {
+		t.Fatalf("newPublisher failed: %v", err)
+	}
+	if err := publisher.Publish(testTopicData()); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var req map[string]string
+	if err := json.Unmarshal(fake.requests["conversations.setTopic"], &req); err != nil {
+		t.Fatalf("conversations.setTopic body should be JSON: %v", err)
+	}
+	if topic := req["topic"]; !strings.HasPrefix(topic, ":f1: 2025 Next: R3/24 Japan") || strings.Contains(topic, "\n") || len(topic) > 250 {
+		t.Errorf("Expected the compact one-line topic, got %q", topic)
+	}
+}
+
+func TestUnknownPublisher(t *testing.T) {
+	if _, err := newPublisher("carrier-pigeon", slackRenderer{}, PublishOptions{}); err == nil {
+		t.Error("Expected an error for an unknown publish target")
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// qualifyingDuration is how long after qualifying starts its results are expected
+const qualifyingDuration = time.Hour
+
+// LapTime is a lap time as the API sends it, e.g. "1:26.983", empty if the driver didn't set one
+type LapTime string
+
+// Duration parses the lap time, returning false if there isn't one
+func (l LapTime) Duration() (time.Duration, bool) {
+	return parseLapTime(string(l))
+}
+
+// QualifyingResult is a driver's qualifying classification with their time in each part
+type QualifyingResult struct {
+	GridPosition finishPosition `json:"gridPosition"`
+	Q1           LapTime        `json:"q1"`
+	Q2           LapTime        `json:"q2"`
+	Q3           LapTime        `json:"q3"`
+	Driver       ResultDriver   `json:"driver"`
+	Team         Team           `json:"team"`
+}
+
+// QualifyingResults holds a round's qualifying classification
+type QualifyingResults struct {
+	RaceID   string             `json:"raceId"`
+	RaceName string             `json:"raceName"`
+	Results  []QualifyingResult `json:"qualyResults"`
+}
+
+// QualifyingResponse is the F1 API response for a round's qualifying results
+type QualifyingResponse struct {
+	API    string            `json:"api"`
+	URL    string            `json:"url"`
+	Season int               `json:"season"`
+	Races  QualifyingResults `json:"races"`
+}
+
+// fetchQualifyingResults gets the qualifying results for a round of a season, in grid order
+func fetchQualifyingResults(season, round int) ([]QualifyingResult, error) {
+	var qualyResp QualifyingResponse
+	path := fmt.Sprintf("/%s/%d/qualy", seasonPath(season), round)
+	if err := fetchAPI(path, fmt.Sprintf("round %d qualifying results", round), &qualyResp); err != nil {
+		return nil, err
+	}
+	if len(qualyResp.Races.Results) == 0 {
+		return nil, errResultsNotPublished
+	}
+	return qualyResp.Races.Results, nil
+}
+
+// inGridWindow reports whether a time falls between the end of a race's qualifying and the race start,
+// when the grid is known but the race hasn't started
+func inGridWindow(race *Race, at time.Time) bool {
+	qualy, hasQualyTime, err := parseSessionTime(race.Schedule.Qualy)
+	if err != nil || !hasQualyTime {
+		return false
+	}
+	start, _, err := parseSessionTime(race.Schedule.Race)
+	if err != nil {
+		return false
+	}
+	return !at.Before(qualy.Add(qualifyingDuration)) && at.Before(start)
+}
+
+// gridSegment returns the pole sitter, or the front row if configured, for the Slack topic,
+// or "" outside the window between qualifying and the race
+func gridSegment(data *TopicData, emoji emojiSet) string {
+	if data.GridErr != nil || len(data.Grid) == 0 {
+		return ""
+	}
+
+	label, count := "Pole", 1
+	if config.ShowFrontRow && len(data.Grid) > 1 {
+		label, count = "Front row", 2
+	}
+	var drivers []string
+	for _, result := range data.Grid[:count] {
+		drivers = append(drivers, emoji.driver(result.Driver.DriverID)+driverAbbr(result.Driver.Driver))
+	}
+	return fmt.Sprintf(" %s: %s", label, strings.Join(drivers, " "))
+}
+
+// writeGrid writes the full starting grid with qualifying times for the detailed output
+func writeGrid(sb *strings.Builder, data *TopicData, emoji emojiSet) {
+	if data.GridErr != nil {
+		sb.WriteString(fmt.Sprintf("Grid error: %v\n\n", data.GridErr))
+		return
+	}
+	if len(data.Grid) == 0 {
+		return
+	}
+
+	sb.WriteString("Starting Grid:\n")
+	for _, result := range data.Grid {
+		var parts []string
+		for i, lap := range []LapTime{result.Q1, result.Q2, result.Q3} {
+			if lap != "" {
+				parts = append(parts, fmt.Sprintf("Q%d %s", i+1, lap))
+			}
+		}
+		times := ""
+		if len(parts) > 0 {
+			times = " - " + strings.Join(parts, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s %s %s (%s)%s\n",
+			result.GridPosition, emoji.driverFlag(result.Driver.Driver), result.Driver.Name, result.Driver.Surname, result.Team.TeamName, times))
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGrid returns the front of a qualifying classification
+func testGrid() []QualifyingResult {
+	return []QualifyingResult{
+		{GridPosition: 1, Q1: "1:27.943", Q2: "1:27.502", Q3: "1:26.983",
+			Driver: ResultDriver{DriverID: "max_verstappen", Driver: Driver{Name: "Max", Surname: "Verstappen", ShortName: "VER", Nationality: "Netherlands"}},
+			Team:   Team{TeamName: "Red Bull Racing"}},
+		{GridPosition: 2, Q1: "1:27.845", Q2: "1:27.146", Q3: "1:26.995",
+			Driver: ResultDriver{DriverID: "norris", Driver: Driver{Name: "Lando", Surname: "Norris", ShortName: "NOR", Nationality: "Great Britain"}},
+			Team:   Team{TeamName: "McLaren Formula 1 Team"}},
+		{GridPosition: 16, Q1: "1:28.554",
+			Driver: ResultDriver{DriverID: "albon", Driver: Driver{Name: "Alexander", Surname: "Albon", ShortName: "ALB", Nationality: "Thailand"}},
+			Team:   Team{TeamName: "Williams Racing"}},
+	}
+}
+
+func TestSlackRendererPole(t *testing.T) {
+	data := testTopicData()
+	data.Grid = testGrid()
+
+	if output := (slackRenderer{}).Render(data); !strings.Contains(output, "Japan :flag-jp: (Apr 4-6) Pole: :f1mv:VER // Standings") {
+		t.Errorf("Topic should show the pole sitter, got %s", output)
+	}
+
+	defer func() { config = Config{} }()
+	config.ShowFrontRow = true
+	if output := (slackRenderer{}).Render(data); !strings.Contains(output, "(Apr 4-6) Front row: :f1mv:VER :f1ln:NOR // ") {
+		t.Errorf("Topic should show the front row, got %s", output)
+	}
+}
+
+func TestTextRendererGrid(t *testing.T) {
+	data := testTopicData()
+	data.Grid = testGrid()
+
+	output := textRenderer{}.Render(data)
+	expected := []string{
+		"Starting Grid:\n1. 🇳🇱 Max Verstappen (Red Bull Racing) - Q1 1:27.943, Q2 1:27.502, Q3 1:26.983\n",
+		"16. 🇹🇭 Alexander Albon (Williams Racing) - Q1 1:28.554\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestInGridWindow(t *testing.T) {
+	race := testTopicData().NextRace // qualifying 2025-04-05 06:00 UTC, race 2025-04-06 05:00 UTC
+
+	tests := map[time.Time]bool{
+		time.Date(2025, 4, 5, 6, 30, 0, 0, time.UTC): false,
+		time.Date(2025, 4, 5, 7, 0, 0, 0, time.UTC):  true,
+		time.Date(2025, 4, 6, 4, 59, 0, 0, time.UTC): true,
+		time.Date(2025, 4, 6, 5, 0, 0, 0, time.UTC):  false,
+	}
+	for at, want := range tests {
+		if got := inGridWindow(race, at); got != want {
+			t.Errorf("inGridWindow at %s = %v, want %v", at.Format(time.RFC3339), got, want)
+		}
+	}
+}
+
+func TestLapTimeDuration(t *testing.T) {
+	if d, ok := LapTime("1:26.983").Duration(); !ok || d != 86983*time.Millisecond {
+		t.Errorf("Unexpected duration %s", d)
+	}
+	if _, ok := LapTime("").Duration(); ok {
+		t.Error("An empty lap time should have no duration")
+	}
+}
+
+func TestFetchTopicDataGrid(t *testing.T) {
+	fixClock(t, time.Date(2025, 4, 5, 8, 0, 0, 0, time.UTC))
+	newFakeF1API(t, map[string]string{
+		"/current": `{"season": 2025, "races": [{"raceId": "japanese_2025", "schedule": {"race": {"date": "2025-04-06", "time": "05:00:00Z"}}}]}`,
+		"/current/next": `{"season": 2025, "round": 1, "race": [{"raceId": "japanese_2025", "schedule": {` +
+			`"race": {"date": "2025-04-06", "time": "05:00:00Z"}, "qualy": {"date": "2025-04-05", "time": "06:00:00Z"}}}]}`,
+		"/2025/1/qualy": `{"season": 2025, "races": {"raceId": "japanese_2025", "qualyResults": [` +
+			`{"gridPosition": 1, "q1": "1:27.943", "q2": "1:27.502", "q3": "1:26.983", "driver": {"driverId": "max_verstappen", "shortName": "VER"}}]}}`,
+	})
+
+	data := fetchTopicData()
+	if data.GridErr != nil || len(data.Grid) != 1 || data.Grid[0].Q3 != "1:26.983" {
+		t.Errorf("Expected the grid between qualifying and the race, got %+v (%v)", data.Grid, data.GridErr)
+	}
+}
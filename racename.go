@@ -0,0 +1,143 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Map circuit IDs to short race names, since circuit IDs are stable across seasons.
+// Circuits the API has used more than one ID for are listed under each of them.
+var circuitShortNames = map[string]string{
+	"albert_park":        "Australia",
+	"shanghai":           "China",
+	"suzuka":             "Japan",
+	"bahrain":            "Bahrain",
+	"jeddah":             "Saudi Arabia",
+	"miami":              "Miami",
+	"imola":              "Emilia Romagna",
+	"monaco":             "Monaco",
+	"catalunya":          "Spain",
+	"madring":            "Madrid",
+	"villeneuve":         "Canada",
+	"gilles_villeneuve":  "Canada",
+	"red_bull_ring":      "Austria",
+	"silverstone":        "Britain",
+	"spa":                "Belgium",
+	"hungaroring":        "Hungary",
+	"zandvoort":          "Netherlands",
+	"monza":              "Italy",
+	"baku":               "Azerbaijan",
+	"marina_bay":         "Singapore",
+	"americas":           "USA",
+	"rodriguez":          "Mexico",
+	"hermanos_rodriguez": "Mexico",
+	"interlagos":         "Brazil",
+	"vegas":              "Las Vegas",
+	"las_vegas":          "Las Vegas",
+	"losail":             "Qatar",
+	"lusail":             "Qatar",
+	"yas_marina":         "Abu Dhabi",
+}
+
+// titleSponsors are stripped from the start of race names the lookup doesn't know about
+var titleSponsors = []string{
+	"Formula 1",
+	"Heineken Silver",
+	"Heineken",
+	"Lenovo",
+	"Qatar Airways",
+	"Aramco",
+	"STC",
+	"Gulf Air",
+	"Crypto.com",
+	"AWS",
+	"MSC Cruises",
+	"Pirelli",
+	"Louis Vuitton",
+	"Etihad Airways",
+	"Singapore Airlines",
+	"MoneyGram",
+	"Rolex",
+	"TAG Heuer",
+	"Santander",
+	"Emirates",
+	"DHL",
+	"Salesforce",
+}
+
+// titleSponsorsByLength holds the title sponsors longest first, so "Heineken Silver" is stripped before "Heineken"
+var titleSponsorsByLength = func() []string {
+	sponsors := append([]string(nil), titleSponsors...)
+	sort.SliceStable(sponsors, func(i, j int) bool { return len(sponsors[i]) > len(sponsors[j]) })
+	return sponsors
+}()
+
+// grandPrixPhrases are removed from race names the lookup doesn't know about, each with its connecting
+// word listed before the bare phrase
+var grandPrixPhrases = []string{
+	"Grand Prix of ", "Grand Prix de ", "Grand Prix du ", "Grand Prix",
+	"Gran Premio de la ", "Gran Premio del ", "Gran Premio de ", "Gran Premio di ", "Gran Premio d'", "Gran Premio",
+	"Grande Prêmio do ", "Grande Prêmio de ", "Grande Prêmio",
+}
+
+// raceYearSuffix matches the season at the end of race IDs and race names
+var raceYearSuffix = regexp.MustCompile(`[_ ]\d{4}$`)
+
+// shortRaceName returns the short name for a race (e.g., "Lenovo Japanese Grand Prix 2025" -> "Japan"),
+// preferring configured names, then the built-in circuit lookup, then stripping the full race name
+func shortRaceName(race *Race) string {
+	raceKey := raceYearSuffix.ReplaceAllString(race.RaceID, "")
+	for _, key := range []string{race.RaceID, raceKey, race.Circuit.CircuitID} {
+		if name, exists := config.RaceNames[key]; exists && key != "" {
+			return name
+		}
+	}
+
+	if name, exists := circuitShortNames[race.Circuit.CircuitID]; exists {
+		return name
+	}
+
+	return stripRaceName(race.RaceName)
+}
+
+// stripRaceName shortens a full race name by removing title sponsors and the "Grand Prix YYYY" suffix,
+// turning a leftover demonym into its country (e.g., "Portuguese" -> "Portugal")
+func stripRaceName(fullName string) string {
+	name := strings.TrimSpace(raceYearSuffix.ReplaceAllString(strings.TrimSpace(fullName), ""))
+
+	// Remove "Grand Prix" and its translations wherever they appear, e.g. "Grand Prix de Monaco",
+	// "Gran Premio d'Italia" or "Japanese Grand Prix"
+	for _, phrase := range grandPrixPhrases {
+		if i := strings.Index(strings.ToLower(name), strings.ToLower(phrase)); i >= 0 {
+			name = strings.TrimSpace(name[:i] + name[i+len(phrase):])
+		}
+	}
+
+	// Strip title sponsors from the start until none are left
+	for stripped := true; stripped; {
+		stripped = false
+		for _, sponsor := range titleSponsorsByLength {
+			if len(name) > len(sponsor) && strings.EqualFold(name[:len(sponsor)], sponsor) && name[len(sponsor)] == ' ' {
+				name = strings.TrimSpace(name[len(sponsor):])
+				stripped = true
+				break
+			}
+		}
+	}
+
+	if name == "" {
+		return strings.TrimSpace(fullName)
+	}
+
+	// A demonym on its own reads better as the country, e.g. "Portuguese Grand Prix" -> "Portugal"
+	for _, c := range countries {
+		for _, demonym := range c.demonyms {
+			if strings.EqualFold(name, demonym) && len(c.names) > 0 {
+				return c.names[0]
+			}
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,93 @@
+package main
+
+import "testing"
+
+func TestShortRaceNameCalendar(t *testing.T) {
+	// Every race on the 2025 and 2026 calendars, as returned by the API
+	tests := []struct {
+		raceID    string
+		circuitID string
+		raceName  string
+		want      string
+	}{
+		{"australian_2025", "albert_park", "Formula 1 Louis Vuitton Australian Grand Prix 2025", "Australia"},
+		{"chinese_2025", "shanghai", "Formula 1 Heineken Chinese Grand Prix 2025", "China"},
+		{"japanese_2025", "suzuka", "Formula 1 Lenovo Japanese Grand Prix 2025", "Japan"},
+		{"bahrain_2025", "bahrain", "Formula 1 Gulf Air Bahrain Grand Prix 2025", "Bahrain"},
+		{"saudi_arabian_2025", "jeddah", "Formula 1 STC Saudi Arabian Grand Prix 2025", "Saudi Arabia"},
+		{"miami_2025", "miami", "Formula 1 Crypto.com Miami Grand Prix 2025", "Miami"},
+		{"emilia_romagna_2025", "imola", "Formula 1 AWS Gran Premio del Made in Italy e dell'Emilia-Romagna 2025", "Emilia Romagna"},
+		{"monaco_2025", "monaco", "Formula 1 TAG Heuer Grand Prix de Monaco 2025", "Monaco"},
+		{"spanish_2025", "catalunya", "Formula 1 Aramco Gran Premio de España 2025", "Spain"},
+		{"canadian_2025", "villeneuve", "Formula 1 Pirelli Grand Prix du Canada 2025", "Canada"},
+		{"austrian_2025", "red_bull_ring", "Formula 1 MSC Cruises Austrian Grand Prix 2025", "Austria"},
+		{"british_2025", "silverstone", "Formula 1 Qatar Airways British Grand Prix 2025", "Britain"},
+		{"belgian_2025", "spa", "Formula 1 Moët & Chandon Belgian Grand Prix 2025", "Belgium"},
+		{"hungarian_2025", "hungaroring", "Formula 1 Lenovo Hungarian Grand Prix 2025", "Hungary"},
+		{"dutch_2025", "zandvoort", "Formula 1 Heineken Dutch Grand Prix 2025", "Netherlands"},
+		{"italian_2025", "monza", "Formula 1 Pirelli Gran Premio d'Italia 2025", "Italy"},
+		{"azerbaijan_2025", "baku", "Formula 1 Qatar Airways Azerbaijan Grand Prix 2025", "Azerbaijan"},
+		{"singapore_2025", "marina_bay", "Formula 1 Singapore Airlines Singapore Grand Prix 2025", "Singapore"},
+		{"united_states_2025", "americas", "Formula 1 MSC Cruises United States Grand Prix 2025", "USA"},
+		{"mexico_city_2025", "rodriguez", "Formula 1 Gran Premio de la Ciudad de México 2025", "Mexico"},
+		{"sao_paulo_2025", "interlagos", "Formula 1 MSC Cruises Grande Prêmio de São Paulo 2025", "Brazil"},
+		{"las_vegas_2025", "vegas", "Formula 1 Heineken Silver Las Vegas Grand Prix 2025", "Las Vegas"},
+		{"qatar_2025", "losail", "Formula 1 Qatar Airways Qatar Grand Prix 2025", "Qatar"},
+		{"abu_dhabi_2025", "yas_marina", "Formula 1 Etihad Airways Abu Dhabi Grand Prix 2025", "Abu Dhabi"},
+		{"spanish_2026", "madring", "Formula 1 Gran Premio de España 2026", "Madrid"},
+		{"barcelona_2026", "catalunya", "Formula 1 Barcelona-Catalunya Grand Prix 2026", "Spain"},
+	}
+	for _, test := range tests {
+		race := &Race{RaceID: test.raceID, RaceName: test.raceName, Circuit: Circuit{CircuitID: test.circuitID}}
+		if got := shortRaceName(race); got != test.want {
+			t.Errorf("shortRaceName(%s) = %q, want %q", test.raceID, got, test.want)
+		}
+	}
+}
+
+func TestStripRaceName(t *testing.T) {
+	tests := map[string]string{
+		"Formula 1 Heineken Silver Las Vegas Grand Prix 2025":   "Las Vegas",
+		"Formula 1 Qatar Airways Qatar Grand Prix 2025":         "Qatar",
+		"Lenovo Japanese Grand Prix 2025":                       "Japan",
+		"Heineken Dutch Grand Prix 2025":                        "Netherlands",
+		"Portuguese Grand Prix 2021":                            "Portugal",
+		"Grand Prix de Monaco 2025":                             "Monaco",
+		"Formula 1 Grand Prix of Atlantis 2030":                 "Atlantis",
+		"Styrian Grand Prix":                                    "Styrian",
+		"Formula 1 Pirelli Grand Prix du Canada 2025":           "Canada",
+		"Gran Premio di San Marino 2006":                        "San Marino",
+		"Formula 1 Gran Premio de la Ciudad de México 2025":     "Ciudad de México",
+		"Formula 1 Aramco Gran Premio de España 2025":           "España",
+		"Formula 1 Pirelli Gran Premio d'Italia 2025":           "Italia",
+		"Formula 1 MSC Cruises Grande Prêmio de São Paulo 2025": "São Paulo",
+		"Grand Prix 2025":                                       "Grand Prix 2025",
+	}
+	for fullName, want := range tests {
+		if got := stripRaceName(fullName); got != want {
+			t.Errorf("stripRaceName(%q) = %q, want %q", fullName, got, want)
+		}
+	}
+}
+
+func TestShortRaceNameConfig(t *testing.T) {
+	oldConfig := config
+	t.Cleanup(func() { config = oldConfig })
+
+	race := &Race{RaceID: "japanese_2025", RaceName: "Formula 1 Lenovo Japanese Grand Prix 2025", Circuit: Circuit{CircuitID: "suzuka"}}
+
+	config = Config{RaceNames: map[string]string{"suzuka": "Suzuka"}}
+	if got := shortRaceName(race); got != "Suzuka" {
+		t.Errorf("Expected circuit override, got %q", got)
+	}
+
+	config.RaceNames["japanese"] = "Nippon"
+	if got := shortRaceName(race); got != "Nippon" {
+		t.Errorf("Expected race override to beat circuit override, got %q", got)
+	}
+
+	config.RaceNames["japanese_2025"] = "Japan '25"
+	if got := shortRaceName(race); got != "Japan '25" {
+		t.Errorf("Expected season-specific override to win, got %q", got)
+	}
+}
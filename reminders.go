@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultReminders are used when the config doesn't set any, keyed by session
+var defaultReminders = map[string][]string{
+	"qualy": {"1h"},
+	"race":  {"30m"},
+}
+
+// sentReminderRetention is how long sent reminders are remembered, long enough to outlast any race weekend
+const sentReminderRetention = 30 * 24 * time.Hour
+
+// reminder is an offset before a session to post a reminder at
+type reminder struct {
+	session string
+	before  time.Duration
+}
+
+// parseReminders validates the configured reminders, falling back to the defaults
+func parseReminders(configured map[string][]string) ([]reminder, error) {
+	if len(configured) == 0 {
+		configured = defaultReminders
+	}
+
+	// Check session keys so typos fail loudly rather than never firing
+	known := map[string]bool{}
+	var keys []string
+	for _, s := range weekendSessions(&Race{}) {
+		known[s.key] = true
+		keys = append(keys, s.key)
+	}
+
+	var reminders []reminder
+	for _, key := range sortedKeys(mapKeys(configured)) {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown session %q in reminders, expected one of %s", key, strings.Join(keys, ", "))
+		}
+		for _, offset := range configured[key] {
+			before, err := time.ParseDuration(offset)
+			if err != nil || before <= 0 {
+				return nil, fmt.Errorf("invalid reminder offset %q for %s, expected a positive duration like 1h or 30m", offset, key)
+			}
+			reminders = append(reminders, reminder{session: key, before: before})
+		}
+	}
+	return reminders, nil
+}
+
+// dueReminder is a reminder ready to post
+type dueReminder struct {
+	// ID identifies the reminder across restarts, e.g. "japanese_2025-qualy-1h0m0s"
+	ID      string
+	Race    *Race
+	Session session
+	Start   time.Time
+	Before  time.Duration
+}
+
+// dueReminders returns the reminders due at a time that haven't been sent, in the order they fell due.
+// A reminder is due from its offset before the session until the session starts.
+func dueReminders(races []Race, reminders []reminder, sent map[string]time.Time, at time.Time) []dueReminder {
+	var due []dueReminder
+	for i := range races {
+		race := &races[i]
+		for _, s := range raceSessions(race) {
+			start, hasTime, _ := parseSessionTime(s.info)
+			if !hasTime || !at.Before(start) {
+				continue
+			}
+			for _, r := range reminders {
+				id := fmt.Sprintf("%s-%s-%s", race.RaceID, s.key, r.before)
+				if r.session != s.key || at.Before(start.Add(-r.before)) {
+					continue
+				}
+				if _, done := sent[id]; done {
+					continue
+				}
+				due = append(due, dueReminder{ID: id, Race: race, Session: s, Start: start, Before: r.before})
+			}
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Start.Add(-due[i].Before).Before(due[j].Start.Add(-due[j].Before))
+	})
+	return due
+}
+
+// reminderMessage builds the reminder post, with the start time shown in each reader's timezone
+func reminderMessage(due dueReminder) slackMessage {
+	emoji := emojiSet{flags: shortcodeFlags, custom: true}
+	until := due.Start.Sub(now()).Round(time.Minute)
+	if until < time.Minute {
+		until = time.Minute
+	}
+	return slackMessage{Text: fmt.Sprintf(":alarm_clock: %s for the %s %s starts in %s, %s",
+		due.Session.name, due.Race.RaceName, emoji.raceFlag(due.Race), formatOffset(until), slackSessionTime(due.Session.info))}
+}
+
+// formatOffset formats a duration for people, e.g. "1 hour" or "1 hour 30 minutes"
+func formatOffset(d time.Duration) string {
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0:
+		return plural(minutes, "minute")
+	case minutes == 0:
+		return plural(hours, "hour")
+	default:
+		return plural(hours, "hour") + " " + plural(minutes, "minute")
+	}
+}
+
+// reminderPoster posts due reminders to a channel, remembering what it has sent in the state directory
+type reminderPoster struct {
+	client    *slackClient
+	channel   string
+	reminders []reminder
+	sent      map[string]time.Time
+}
+
+// newReminderPoster creates a poster using the configured reminders and the sent reminders saved by earlier runs
+func newReminderPoster(client *slackClient, channel string) (*reminderPoster, error) {
+	reminders, err := parseReminders(config.Reminders)
+	if err != nil {
+		return nil, err
+	}
+	if stateDir == "" {
+		log.Printf("WARNING: no -state-dir, reminders may be posted again after a restart")
+	}
+	sent, err := loadSentReminders()
+	if err != nil {
+		return nil, err
+	}
+	return &reminderPoster{client: client, channel: channel, reminders: reminders, sent: sent}, nil
+}
+
+// loadSentReminders reads the reminders sent by earlier runs
+func loadSentReminders() (map[string]time.Time, error) {
+	sent := map[string]time.Time{}
+	if _, err := loadState("reminders.json", "sent reminders", &sent); err != nil {
+		return nil, err
+	}
+	return sent, nil
+}
+
+// save writes the sent reminders, forgetting those old enough to never fall due again
+func (p *reminderPoster) save() error {
+	for id, sentAt := range p.sent {
+		if now().Sub(sentAt) > sentReminderRetention {
+			delete(p.sent, id)
+		}
+	}
+	return saveState("reminders.json", "sent reminders", p.sent)
+}
+
+// check posts every reminder that's due, saving after each so a crash can't cause a repeat
+func (p *reminderPoster) check(data *TopicData) error {
+	races := data.Calendar
+	if len(races) == 0 && data.NextRace != nil {
+		races = []Race{*data.NextRace}
+	}
+
+	for _, due := range dueReminders(races, p.reminders, p.sent, now()) {
+		if err := p.client.PostMessage(p.channel, reminderMessage(due)); err != nil {
+			return fmt.Errorf("error posting reminder %s: %v", due.ID, err)
+		}
+		log.Printf("Posted reminder %s to %s", due.ID, p.channel)
+		p.sent[due.ID] = now()
+		if err := p.save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runChecks calls check every interval with the data from get until stop is closed, naming it what in errors
+func runChecks(what string, check func(*TopicData) error, get func() *TopicData, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if data := get(); data != nil {
+			if err := check(data); err != nil {
+				log.Printf("Error checking %s: %v", what, err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// remindCommand posts reminders before sessions, either once for cron or as a daemon
+func remindCommand(args []string) error {
+	fs := flag.NewFlagSet("remind", flag.ExitOnError)
+	channel := fs.String("channel", "", "Slack channel ID to post reminders to")
+	token := fs.String("token", "", "Slack bot token (defaults to $SLACK_TOKEN)")
+	once := fs.Bool("once", false, "Post any due reminders and exit, for running from cron")
+	interval := fs.Duration("interval", time.Minute, "How often to check for due reminders")
+	refresh := fs.Duration("refresh", time.Hour, "How often to refresh the calendar from the F1 API")
+	fs.Parse(args)
+
+	client, err := channelClient(PublishOptions{Channel: *channel, Token: *token})
+	if err != nil {
+		return err
+	}
+	poster, err := newReminderPoster(client, *channel)
+	if err != nil {
+		return err
+	}
+
+	if *once {
+		return poster.check(fetchTopicData())
+	}
+	if *interval <= 0 || *refresh <= 0 {
+		return errors.New("-interval and -refresh must be positive")
+	}
+
+	server := newTopicServer(fetchTopicData)
+	server.refresh()
+	go server.run(*refresh, nil)
+	log.Printf("Checking for due reminders every %s", *interval)
+	runChecks("reminders", poster.check, server.current, *interval, nil)
+	return nil
+}
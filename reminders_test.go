@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reminderIDs returns the IDs of due reminders
+func reminderIDs(due []dueReminder) string {
+	var ids []string
+	for _, d := range due {
+		ids = append(ids, d.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestParseReminders(t *testing.T) {
+	reminders, err := parseReminders(nil)
+	if err != nil || len(reminders) != 2 {
+		t.Fatalf("Expected the default reminders, got %+v (%v)", reminders, err)
+	}
+	if reminders[0] != (reminder{"qualy", time.Hour}) || reminders[1] != (reminder{"race", 30 * time.Minute}) {
+		t.Errorf("Unexpected default reminders: %+v", reminders)
+	}
+
+	for _, configured := range []map[string][]string{
+		{"quali": {"1h"}},
+		{"race": {"soon"}},
+		{"race": {"-5m"}},
+	} {
+		if _, err := parseReminders(configured); err == nil {
+			t.Errorf("Expected an error for %v", configured)
+		}
+	}
+}
+
+func TestDueReminders(t *testing.T) {
+	races := []Race{*testTopicData().NextRace}
+	reminders := []reminder{{"qualy", time.Hour}, {"race", 30 * time.Minute}, {"race", 24 * time.Hour}}
+	sent := map[string]time.Time{}
+
+	// Qualifying is 2025-04-05 06:00 UTC and the race 2025-04-06 05:00 UTC
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2025, 4, 5, 4, 59, 0, 0, time.UTC), ""},
+		{time.Date(2025, 4, 5, 5, 0, 0, 0, time.UTC), "japanese_2025-qualy-1h0m0s,japanese_2025-race-24h0m0s"},
+		{time.Date(2025, 4, 5, 6, 0, 0, 0, time.UTC), "japanese_2025-race-24h0m0s"},
+		{time.Date(2025, 4, 6, 4, 45, 0, 0, time.UTC), "japanese_2025-race-24h0m0s,japanese_2025-race-30m0s"},
+		{time.Date(2025, 4, 6, 5, 0, 0, 0, time.UTC), ""},
+	}
+	for _, test := range tests {
+		if got := reminderIDs(dueReminders(races, reminders, sent, test.at)); got != test.want {
+			t.Errorf("Due at %s = %q, want %q", test.at.Format(time.RFC3339), got, test.want)
+		}
+	}
+
+	sent["japanese_2025-race-24h0m0s"] = time.Date(2025, 4, 5, 5, 0, 0, 0, time.UTC)
+	if got := reminderIDs(dueReminders(races, reminders, sent, time.Date(2025, 4, 6, 4, 45, 0, 0, time.UTC))); got != "japanese_2025-race-30m0s" {
+		t.Errorf("Sent reminders shouldn't be due again, got %q", got)
+	}
+}
+
+func TestReminderPosterAcrossRestarts(t *testing.T) {
+	useStateDir(t)
+	fake := newFakeSlack(t)
+	fixClock(t, time.Date(2025, 4, 5, 5, 0, 0, 0, time.UTC))
+
+	poster, err := newReminderPoster(newSlackClient("xoxb-test"), "C123")
+	if err != nil {
+		t.Fatalf("newReminderPoster failed: %v", err)
+	}
+	if err := poster.check(testTopicData()); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &msg); err != nil {
+		t.Fatalf("chat.postMessage body should be JSON: %v", err)
+	}
+	expected := ":alarm_clock: Qualifying for the Lenovo Japanese Grand Prix 2025 :flag-jp: starts in 1 hour, " +
+		"<!date^1743832800^{date_short_pretty} at {time}|Sat Apr 5 06:00 UTC>"
+	if msg.Channel != "C123" || msg.Text != expected {
+		t.Errorf("Unexpected reminder:\n got: %+v\nwant: %s", msg, expected)
+	}
+
+	// A restarted poster remembers the reminder was sent
+	delete(fake.requests, "chat.postMessage")
+	fixClock(t, time.Date(2025, 4, 5, 5, 10, 0, 0, time.UTC))
+	restarted, err := newReminderPoster(newSlackClient("xoxb-test"), "C123")
+	if err != nil {
+		t.Fatalf("newReminderPoster failed: %v", err)
+	}
+	if err := restarted.check(testTopicData()); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if _, posted := fake.requests["chat.postMessage"]; posted {
+		t.Error("The reminder shouldn't be posted again after a restart")
+	}
+}
+
+func TestReminderPosterForgetsOldReminders(t *testing.T) {
+	useStateDir(t)
+	fixClock(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	poster := &reminderPoster{sent: map[string]time.Time{
+		"japanese_2025-qualy-1h0m0s": time.Date(2025, 4, 5, 5, 0, 0, 0, time.UTC),
+		"spanish_2025-qualy-1h0m0s":  time.Date(2025, 5, 31, 13, 0, 0, 0, time.UTC),
+	}}
+	if err := poster.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	sent, err := loadSentReminders()
+	if err != nil {
+		t.Fatalf("loadSentReminders failed: %v", err)
+	}
+	if _, kept := sent["japanese_2025-qualy-1h0m0s"]; kept || len(sent) != 1 {
+		t.Errorf("Only recent reminders should be kept, got %v", sent)
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Minute: "30 minutes",
+		time.Hour:        "1 hour",
+		90 * time.Minute: "1 hour 30 minutes",
+		24 * time.Hour:   "24 hours",
+	}
+	for d, want := range tests {
+		if got := formatOffset(d); got != want {
+			t.Errorf("formatOffset(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Renderer turns fetched F1 data into a particular output format
+type Renderer interface {
+	Render(data *TopicData) string
+}
+
+// Map output format names to their renderers
+var renderers = map[string]Renderer{
+	"text":     textRenderer{},
+	"slack":    slackRenderer{},
+	"markdown": markdownRenderer{},
+	"html":     htmlRenderer{},
+	"blocks":   blockKitRenderer{},
+	"discord":  discordRenderer{},
+	"teams":    msTeamsRenderer{},
+}
+
+// rendererNames returns the sorted list of available output formats
+func rendererNames() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// digestStandingsLimit is how many championship positions the digest formats show
+const digestStandingsLimit = 10
+
+// textRenderer renders the detailed plain text output
+type textRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r textRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds the F1 information string
+func (r textRenderer) Render(data *TopicData) string {
+	var sb strings.Builder
+	emoji := emojiSet{flags: r.flags.or(unicodeFlags)}
+
+	if data.OffSeason {
+		return r.renderOffSeason(data, emoji)
+	}
+
+	sb.WriteString(fmt.Sprintf("F1 Data for %d\n\n", data.Season))
+
+	// Display next race
+	if data.NextRaceErr != nil {
+		sb.WriteString(fmt.Sprintf("Next race: %v\n\n", data.NextRaceErr))
+	} else {
+		nextRace := data.NextRace
+		raceDate, err := time.Parse("2006-01-02", nextRace.Schedule.Race.Date)
+		if err != nil {
+			log.Printf("Error parsing race date: %v", err)
+			sb.WriteString("Next Race: Unknown (date parsing error)\n\n")
+		} else {
+			raceTime, timeErr := time.Parse("15:04:05Z", nextRace.Schedule.Race.Time)
+			timeStr := ""
+			if timeErr == nil {
+				timeStr = fmt.Sprintf(" at %s", raceTime.Format("15:04 MST"))
+			}
+
+			sb.WriteString(fmt.Sprintf("Next Race: %s (Round %d)\n", nextRace.RaceName, data.Round))
+			sb.WriteString(fmt.Sprintf("Circuit: %s\n", nextRace.Circuit.CircuitName))
+			writeCircuitCard(&sb, data, nextRace.Circuit)
+			sb.WriteString(fmt.Sprintf("Date: %s%s\n", raceDate.Format("January 2, 2006"), timeStr))
+			sb.WriteString(fmt.Sprintf("Country: %s %s\n\n", nextRace.Country, emoji.raceFlag(nextRace)))
+		}
+	}
+
+	// Display the starting grid between qualifying and the race
+	writeGrid(&sb, data, emoji)
+
+	// Display driver standings
+	if data.DriversErr != nil {
+		sb.WriteString(fmt.Sprintf("Driver standings error: %v\n\n", data.DriversErr))
+	} else {
+		sb.WriteString("Driver Standings:\n")
+		for i := 0; i < 3 && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			sb.WriteString(fmt.Sprintf("%d. %s %s %s (%s) - %.1f points\n",
+				driver.Position, emoji.driverFlag(driver.Driver), driver.Driver.Name, driver.Driver.Surname, driver.Team.TeamName, driver.Points))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Display constructor/team standings
+	if data.TeamsErr != nil {
+		sb.WriteString(fmt.Sprintf("Constructor standings error: %v\n", data.TeamsErr))
+	} else {
+		sb.WriteString("Constructor Standings:\n")
+		for i := 0; i < 3 && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+			sb.WriteString(fmt.Sprintf("%d. %s - %.1f points\n",
+				team.Position, team.Team.TeamName, team.Points))
+		}
+	}
+
+	// Display fantasy league standings when a leaderboard is configured
+	if data.FantasyErr != nil {
+		sb.WriteString(fmt.Sprintf("\nFantasy league error: %v\n", data.FantasyErr))
+	} else if len(data.Fantasy) > 0 {
+		sb.WriteString(fmt.Sprintf("\nFantasy League (%s):\n", config.Fantasy.code()))
+		for _, entry := range data.Fantasy {
+			sb.WriteString(fmt.Sprintf("%d. %s - %.0f points\n", entry.Position, entry.Name, entry.Points))
+		}
+	}
+
+	return sb.String()
+}
+
+// slackRenderer renders the compact Slack topic with emojis
+type slackRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r slackRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds a compact Slack topic with emojis for F1 information
+func (r slackRenderer) Render(data *TopicData) string {
+	var sb strings.Builder
+	emoji := emojiSet{flags: r.flags.or(shortcodeFlags), custom: true}
+
+	if data.OffSeason {
+		return checkTopicLength(r.renderOffSeason(data, emoji))
+	}
+
+	// Start with F1 emoji and year
+	sb.WriteString(fmt.Sprintf("%s %d ", emoji.f1(), data.Season))
+
+	// Next race
+	if data.NextRaceErr != nil {
+		sb.WriteString("Next: No upcoming races // ")
+	} else {
+		nextRace, round := data.NextRace, data.Round
+
+		// Parse race date
+		raceDate, err := time.Parse("2006-01-02", nextRace.Schedule.Race.Date)
+		if err != nil {
+			log.Printf("Error parsing race date: %v", err)
+			sb.WriteString(fmt.Sprintf("Next: %s %s%s // ", roundLabel(round, data.TotalRaces), shortRaceName(nextRace), gridSegment(data, emoji)))
+		} else {
+			// Calculate race weekend dates (Friday-Sunday)
+			raceWeekendStart := raceDate.AddDate(0, 0, -2) // Friday is typically 2 days before race day (Sunday)
+
+			// Format as "Next: R[round]/[total] [race] :flag-xx: (Mon DD-DD)", with the pole once qualifying is done
+			sb.WriteString(fmt.Sprintf("Next: %s %s %s (%s %d-%d)%s // ",
+				roundLabel(round, data.TotalRaces),
+				shortRaceName(nextRace),
+				emoji.raceFlag(nextRace),
+				raceWeekendStart.Format("Jan"),
+				raceWeekendStart.Day(),
+				raceDate.Day(),
+				gridSegment(data, emoji)))
+		}
+	}
+
+	// Driver standings
+	if data.DriversErr != nil {
+		sb.WriteString("Standings: No data // ")
+	} else {
+		sb.WriteString("Standings: ")
+
+		// Add top 3 drivers
+		for i := 0; i < 3 && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+
+			// Format as "[driverEmoji]ABBR flagEmoji (points)"
+			sb.WriteString(fmt.Sprintf("%s%s %s (%.0f)",
+				emoji.driver(driver.DriverID),
+				driverAbbr(driver.Driver),
+				emoji.driverFlag(driver.Driver),
+				driver.Points))
+
+			// Add comma if not the last driver
+			if i < 2 && i+1 < len(data.Drivers) {
+				sb.WriteString(", ")
+			}
+		}
+
+		// Separator between drivers and constructors
+		sb.WriteString("; ")
+	}
+
+	// Constructor standings
+	if data.TeamsErr != nil {
+		sb.WriteString("No constructor data // ")
+	} else {
+		// Add top 3 constructors
+		for i := 0; i < 3 && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+
+			// Format as "teamEmoji ABBR (points)"
+			sb.WriteString(fmt.Sprintf("%s%s (%.0f)",
+				emoji.team(team.TeamID),
+				teamAbbr(team),
+				team.Points))
+
+			// Add comma if not the last team
+			if i < 2 && i+1 < len(data.Teams) {
+				sb.WriteString(", ")
+			}
+		}
+	}
+
+	// Add the closest teammate battle, if configured
+	sb.WriteString(closestBattleSegment(data, emoji))
+
+	// Add fantasy league
+	sb.WriteString(fantasySegment(data))
+
+	return checkTopicLength(sb.String())
+}
+
+// checkTopicLength returns the topic, or an error message if it exceeds the 250 character limit.
+// Slack counts each character, including emoji codes (e.g., ":flag-jp:" is 9 characters)
+func checkTopicLength(topic string) string {
+	if len(topic) > 250 {
+		log.Printf("WARNING: Slack topic exceeds 250 character limit (%d characters)", len(topic))
+		return fmt.Sprintf("ERROR: Slack topic exceeds 250 character limit (%d characters)", len(topic))
+	}
+	return topic
+}
+
+// roundLabel formats a round for the Slack topic, e.g. "R3/24", leaving out the total if the calendar is unknown
+func roundLabel(round, totalRaces int) string {
+	if totalRaces == 0 {
+		return fmt.Sprintf("R%d", round)
+	}
+	return fmt.Sprintf("R%d/%d", round, totalRaces)
+}
+
+// markdownRenderer renders a digest using GitHub-flavoured markdown tables
+type markdownRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r markdownRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds a markdown digest of the next race and championship standings
+func (r markdownRenderer) Render(data *TopicData) string {
+	var sb strings.Builder
+	emoji := emojiSet{flags: r.flags.or(unicodeFlags)}
+
+	sb.WriteString(fmt.Sprintf("# F1 %d\n\n", data.Season))
+
+	switch {
+	case data.OffSeason:
+		sb.WriteString(fmt.Sprintf("## Off-Season\n\n_The %d season is over_\n\n", data.Season))
+		sb.WriteString("| | |\n|---|---|\n")
+		for _, fact := range offSeasonFacts(data, emoji) {
+			sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", fact.Label, markdownEscape(fact.Value)))
+		}
+		sb.WriteString("\n")
+	case data.NextRaceErr != nil:
+		sb.WriteString("## Next Race\n\n")
+		sb.WriteString(fmt.Sprintf("_%s_\n\n", markdownEscape(data.NextRaceErr.Error())))
+	default:
+		race := data.NextRace
+		sb.WriteString("## Next Race\n\n")
+		sb.WriteString("| | |\n|---|---|\n")
+		sb.WriteString(fmt.Sprintf("| **Race** | %s (Round %d) |\n", markdownEscape(race.RaceName), data.Round))
+		sb.WriteString(fmt.Sprintf("| **Circuit** | %s |\n", markdownEscape(race.Circuit.CircuitName)))
+		sb.WriteString(fmt.Sprintf("| **Country** | %s %s |\n", markdownEscape(race.Country), emoji.raceFlag(race)))
+		if qualy := formatSessionTime(race.Schedule.Qualy); qualy != "" {
+			sb.WriteString(fmt.Sprintf("| **Qualifying** | %s |\n", qualy))
+		}
+		if raceTime := formatSessionTime(race.Schedule.Race); raceTime != "" {
+			sb.WriteString(fmt.Sprintf("| **Race** | %s |\n", raceTime))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Driver Standings\n\n")
+	if data.DriversErr != nil {
+		sb.WriteString(fmt.Sprintf("_%s_\n\n", markdownEscape(data.DriversErr.Error())))
+	} else {
+		sb.WriteString("| Pos | Driver | Team | Points | Wins |\n|---:|---|---|---:|---:|\n")
+		for i := 0; i < digestStandingsLimit && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			sb.WriteString(fmt.Sprintf("| %d | %s %s %s | %s | %.1f | %d |\n",
+				driver.Position,
+				emoji.driverFlag(driver.Driver),
+				markdownEscape(driver.Driver.Name),
+				markdownEscape(driver.Driver.Surname),
+				markdownEscape(driver.Team.TeamName),
+				driver.Points,
+				driver.Wins))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Constructor Standings\n\n")
+	if data.TeamsErr != nil {
+		sb.WriteString(fmt.Sprintf("_%s_\n", markdownEscape(data.TeamsErr.Error())))
+	} else {
+		sb.WriteString("| Pos | Team | Points | Wins |\n|---:|---|---:|---:|\n")
+		for i := 0; i < digestStandingsLimit && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+			sb.WriteString(fmt.Sprintf("| %d | %s | %.1f | %d |\n",
+				team.Position,
+				markdownEscape(team.Team.TeamName),
+				team.Points,
+				team.Wins))
+		}
+	}
+
+	return sb.String()
+}
+
+// markdownEscape escapes characters that would break a markdown table cell
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`").Replace(s)
+}
+
+// parseSessionTime parses a session's date and time, reporting whether the time of day was known
+func parseSessionTime(info TimeInfo) (t time.Time, hasTime bool, err error) {
+	date, err := time.Parse("2006-01-02", info.Date)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05Z", info.Date+" "+info.Time); err == nil {
+		return t, true, nil
+	}
+	return date, false, nil
+}
+
+// formatSessionTime formats a session date and time for the digest formats, or "" if unparseable
+func formatSessionTime(info TimeInfo) string {
+	t, hasTime, err := parseSessionTime(info)
+	if err != nil {
+		return ""
+	}
+	if hasTime {
+		return t.Format("Mon January 2, 2006 at 15:04") + " UTC"
+	}
+	return t.Format("Mon January 2, 2006")
+}
+
+// htmlRenderer renders a self-contained HTML page
+type htmlRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r htmlRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// htmlData is the data passed to htmlTemplate
+type htmlData struct {
+	*TopicData
+	Emoji emojiSet
+}
+
+// OffSeasonFacts returns the off-season summary for the template
+func (d htmlData) OffSeasonFacts() []offSeasonFact {
+	return offSeasonFacts(d.TopicData, d.Emoji)
+}
+
+// htmlTemplate is the self-contained page used by htmlRenderer, with inline styles and no external assets
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"session": formatSessionTime,
+	"points":  func(p float64) string { return fmt.Sprintf("%.1f", p) },
+	"raceFlag": func(emoji emojiSet, race *Race) string {
+		return emoji.raceFlag(race)
+	},
+	"driverFlag": func(emoji emojiSet, driver Driver) string {
+		return emoji.driverFlag(driver)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>F1 {{.Season}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 48em; margin: 2em auto; padding: 0 1em; color: #15151e; }
+h1 { border-bottom: 3px solid #e10600; padding-bottom: .25em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { text-align: left; padding: .35em .6em; border-bottom: 1px solid #ddd; }
+th { background: #f4f4f4; }
+td.num, th.num { text-align: right; }
+.error { color: #888; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>F1 {{.Season}}</h1>
+{{- if .OffSeason}}
+<h2>Off-Season</h2>
+<p>The {{.Season}} season is over</p>
+<table>
+{{- range .OffSeasonFacts}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- else if .NextRaceErr}}
+<h2>Next Race</h2>
+<p class="error">{{.NextRaceErr}}</p>
+{{- else}}
+<h2>Next Race</h2>
+<table>
+<tr><th>Race</th><td>{{.NextRace.RaceName}} (Round {{.Round}})</td></tr>
+<tr><th>Circuit</th><td>{{.NextRace.Circuit.CircuitName}}</td></tr>
+<tr><th>Country</th><td>{{.NextRace.Country}} {{raceFlag $.Emoji .NextRace}}</td></tr>
+{{- with session .NextRace.Schedule.Qualy}}
+<tr><th>Qualifying</th><td>{{.}}</td></tr>
+{{- end}}
+{{- with session .NextRace.Schedule.Race}}
+<tr><th>Race</th><td>{{.}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Driver Standings</h2>
+{{- if .DriversErr}}
+<p class="error">{{.DriversErr}}</p>
+{{- else}}
+<table>
+<tr><th class="num">Pos</th><th>Driver</th><th>Team</th><th class="num">Points</th><th class="num">Wins</th></tr>
+{{- range .Drivers}}
+<tr><td class="num">{{.Position}}</td><td>{{driverFlag $.Emoji .Driver}} {{.Driver.Name}} {{.Driver.Surname}}</td><td>{{.Team.TeamName}}</td><td class="num">{{points .Points}}</td><td class="num">{{.Wins}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Constructor Standings</h2>
+{{- if .TeamsErr}}
+<p class="error">{{.TeamsErr}}</p>
+{{- else}}
+<table>
+<tr><th class="num">Pos</th><th>Team</th><th class="num">Points</th><th class="num">Wins</th></tr>
+{{- range .Teams}}
+<tr><td class="num">{{.Position}}</td><td>{{.Team.TeamName}}</td><td class="num">{{points .Points}}</td><td class="num">{{.Wins}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// Render builds a self-contained HTML digest of the next race and championship standings
+func (r htmlRenderer) Render(data *TopicData) string {
+	// Trim the standings to the digest limit without modifying the caller's data
+	trimmed := *data
+	trimmed.Drivers = data.Drivers[:min(len(data.Drivers), digestStandingsLimit)]
+	trimmed.Teams = data.Teams[:min(len(data.Teams), digestStandingsLimit)]
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, htmlData{TopicData: &trimmed, Emoji: emojiSet{flags: r.flags.or(unicodeFlags)}}); err != nil {
+		log.Printf("Error rendering HTML: %v", err)
+		return fmt.Sprintf("<!-- error rendering HTML: %s -->\n", template.HTMLEscapeString(err.Error()))
+	}
+	return buf.String()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testTopicData returns a fixed set of fetched data so renderers can be tested offline
+func testTopicData() *TopicData {
+	return &TopicData{
+		Season:     2025,
+		TotalRaces: 24,
+		Round:      3,
+		NextRace: &Race{
+			RaceID:   "japanese_2025",
+			RaceName: "Lenovo Japanese Grand Prix 2025",
+			Schedule: Schedule{
+				Race:  TimeInfo{Date: "2025-04-06", Time: "05:00:00Z"},
+				Qualy: TimeInfo{Date: "2025-04-05", Time: "06:00:00Z"},
+			},
+			Circuit: Circuit{CircuitID: "suzuka", CircuitName: "Suzuka International Racing Course"},
+			Country: "Japan",
+		},
+		Drivers: []DriverStanding{
+			{DriverID: "norris", TeamID: "mclaren", Points: 44, Position: 1, Wins: 1,
+				Driver: Driver{Name: "Lando", Surname: "Norris", Nationality: "Great Britain", ShortName: "NOR"},
+				Team:   Team{TeamName: "McLaren Formula 1 Team"}},
+			{DriverID: "max_verstappen", TeamID: "red_bull", Points: 36, Position: 2,
+				Driver: Driver{Name: "Max", Surname: "Verstappen", Nationality: "Netherlands", ShortName: "VER"},
+				Team:   Team{TeamName: "Red Bull Racing"}},
+			{DriverID: "russell", TeamID: "mercedes", Points: 35, Position: 3,
+				Driver: Driver{Name: "George", Surname: "Russell", Nationality: "Great Britain", ShortName: "RUS"},
+				Team:   Team{TeamName: "Mercedes Formula 1 Team"}},
+		},
+		Teams: []TeamStanding{
+			{TeamID: "mclaren", Points: 78, Position: 1, Wins: 2, Team: Team{TeamName: "McLaren Formula 1 Team"}},
+			{TeamID: "mercedes", Points: 57, Position: 2, Team: Team{TeamName: "Mercedes Formula 1 Team"}},
+			{TeamID: "red_bull", Points: 36, Position: 3, Team: Team{TeamName: "Red Bull Racing"}},
+		},
+	}
+}
+
+func TestTextRenderer(t *testing.T) {
+	output := textRenderer{}.Render(testTopicData())
+
+	expected := []string{
+		"F1 Data for 2025",
+		"Next Race: Lenovo Japanese Grand Prix 2025 (Round 3)",
+		"Date: April 6, 2025 at 05:00 UTC",
+		"Country: Japan 🇯🇵",
+		"1. 🇬🇧 Lando Norris (McLaren Formula 1 Team) - 44.0 points",
+		"1. McLaren Formula 1 Team - 78.0 points",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestSlackRenderer(t *testing.T) {
+	output := slackRenderer{}.Render(testTopicData())
+
+	expected := ":f1: 2025 Next: R3/24 Japan :flag-jp: (Apr 4-6) // " +
+		"Standings: :f1ln:NOR :gb: (44), :f1mv:VER :flag-nl: (36), :f1gr:RUS :gb: (35); " +
+		":m1::f1tl:MCL (78), :f1tm:MER (57), :f1tr:RBR (36) // Fantasy: `thanksai`"
+	if output != expected {
+		t.Errorf("Unexpected Slack topic:\n got: %s\nwant: %s", output, expected)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	output := markdownRenderer{}.Render(testTopicData())
+
+	expected := []string{
+		"# F1 2025",
+		"| **Race** | Lenovo Japanese Grand Prix 2025 (Round 3) |",
+		"| **Qualifying** | Sat April 5, 2025 at 06:00 UTC |",
+		"| Pos | Driver | Team | Points | Wins |",
+		"| 1 | 🇬🇧 Lando Norris | McLaren Formula 1 Team | 44.0 | 1 |",
+		"| 3 | Red Bull Racing | 36.0 | 0 |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownRendererErrors(t *testing.T) {
+	data := testTopicData()
+	data.NextRaceErr = errors.New("no upcoming races found")
+	data.TeamsErr = errors.New("no data found: a|b")
+
+	output := markdownRenderer{}.Render(data)
+	if !strings.Contains(output, "_no upcoming races found_") {
+		t.Errorf("Output should contain next race error, got:\n%s", output)
+	}
+	if !strings.Contains(output, `_no data found: a\|b_`) {
+		t.Errorf("Output should contain escaped team error, got:\n%s", output)
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	data := testTopicData()
+	data.Teams[0].Team.TeamName = "McLaren <Formula 1> & Co"
+
+	output := htmlRenderer{}.Render(data)
+
+	if !strings.HasPrefix(output, "<!DOCTYPE html>") {
+		t.Error("Output should be a complete HTML document")
+	}
+	if strings.Contains(output, "<link") || strings.Contains(output, "<script") {
+		t.Error("Output should be self-contained")
+	}
+	if !strings.Contains(output, "McLaren &lt;Formula 1&gt; &amp; Co") {
+		t.Error("Output should escape team names")
+	}
+	if !strings.Contains(output, "<td>🇬🇧 Lando Norris</td>") {
+		t.Errorf("Output should contain driver standings, got:\n%s", output)
+	}
+}
+
+func TestRendererFlagStyles(t *testing.T) {
+	tests := []struct {
+		format string
+		style  flagStyle
+		want   string
+	}{
+		{"text", "", "Country: Japan 🇯🇵"},
+		{"text", shortcodeFlags, "Country: Japan :flag-jp:"},
+		{"slack", "", "Japan :flag-jp:"},
+		{"slack", unicodeFlags, "Japan 🇯🇵"},
+		{"markdown", shortcodeFlags, "| 1 | :gb: Lando Norris |"},
+		{"html", shortcodeFlags, "<td>:gb: Lando Norris</td>"},
+		{"discord", shortcodeFlags, "Grand Prix 2025 :flag-jp:"},
+	}
+	for _, test := range tests {
+		renderer := renderers[test.format]
+		if test.style != "" {
+			renderer = renderer.(flagStyler).withFlags(test.style)
+		}
+		if output := renderer.Render(testTopicData()); !strings.Contains(output, test.want) {
+			t.Errorf("%s renderer with %q flags should contain %q, got:\n%s", test.format, test.style, test.want, output)
+		}
+	}
+}
+
+func TestRenderersSupportFlagStyles(t *testing.T) {
+	for name, renderer := range renderers {
+		if _, ok := renderer.(flagStyler); !ok {
+			t.Errorf("%s renderer does not support -emoji-style", name)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// now returns the current time, a variable so tests can fix the clock
+var now = time.Now
+
+// Snapshot selects the season and point in it to fetch, the zero value being the live current season
+type Snapshot struct {
+	// Season is the championship year, 0 for the current season
+	Season int
+	// AsOfRound shows standings as they were after this round, 0 for the latest standings
+	AsOfRound int
+}
+
+// live reports whether the snapshot is the current season as it stands
+func (s Snapshot) live() bool {
+	return s.Season == 0 && s.AsOfRound == 0
+}
+
+// snapshot is the season and round selected with -season and -as-of-round
+var snapshot Snapshot
+
+// SeasonResponse is the F1 API response for a season's calendar
+type SeasonResponse struct {
+	API          string       `json:"api"`
+	URL          string       `json:"url"`
+	Total        int          `json:"total"`
+	Season       int          `json:"season"`
+	Championship Championship `json:"championship"`
+	Races        []Race       `json:"races"`
+}
+
+// fetchCalendar gets a season's races in round order, where season 0 is the current season
+func fetchCalendar(season int) (*SeasonResponse, error) {
+	var seasonResp SeasonResponse
+	if err := fetchAPI("/"+seasonPath(season), "season calendar", &seasonResp); err != nil {
+		return nil, err
+	}
+	if len(seasonResp.Races) == 0 {
+		return nil, fmt.Errorf("no races found for season %s", seasonPath(season))
+	}
+	return &seasonResp, nil
+}
+
+// nextRaceAfter picks the next race from a calendar and its round number. With asOfRound it's the round after,
+// otherwise it's the first race that hasn't happened yet.
+func nextRaceAfter(races []Race, asOfRound int) (*Race, int, error) {
+	if asOfRound > len(races) {
+		return nil, 0, fmt.Errorf("round %d is beyond the %d races in the season", asOfRound, len(races))
+	}
+	if asOfRound > 0 {
+		if asOfRound == len(races) {
+			return nil, 0, fmt.Errorf("no upcoming races found")
+		}
+		return &races[asOfRound], asOfRound + 1, nil
+	}
+
+	today := now().UTC().Format("2006-01-02")
+	for i := range races {
+		if races[i].Schedule.Race.Date >= today {
+			return &races[i], i + 1, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("no upcoming races found")
+}
+
+// finishPosition is a classified position, which the API sends as a number or as text such as "NC"
+// for unclassified finishers, who are left at 0
+type finishPosition int
+
+// UnmarshalJSON accepts a position as a number or a string
+func (p *finishPosition) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err == nil {
+		*p = finishPosition(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid position %s", b)
+	}
+	n, _ = strconv.Atoi(s)
+	*p = finishPosition(n)
+	return nil
+}
+
+// ResultDriver is a driver as listed in race results, which carry the driver ID inline
+type ResultDriver struct {
+	DriverID string `json:"driverId"`
+	Driver
+}
+
+// RaceResult is a single driver's finish in a race or sprint
+type RaceResult struct {
+	Position finishPosition `json:"position"`
+	Points   float64        `json:"points"`
+	Grid     finishPosition `json:"grid"`
+	Time     string         `json:"time"`
+	FastLap  string         `json:"fastLap"`
+	Retired  string         `json:"retired"`
+	Driver   ResultDriver   `json:"driver"`
+	Team     Team           `json:"team"`
+}
+
+// RaceResults holds a round's classification
+type RaceResults struct {
+	RaceID   string  `json:"raceId"`
+	RaceName string  `json:"raceName"`
+	Date     string  `json:"date"`
+	Time     string  `json:"time"`
+	Circuit  Circuit `json:"circuit"`
+	// Results holds race results, SprintResults the sprint results from the sprint endpoint
+	Results       []RaceResult `json:"results"`
+	SprintResults []RaceResult `json:"sprintRaceResults"`
+}
+
+// RaceResultsResponse is the F1 API response for a round's race or sprint results
+type RaceResultsResponse struct {
+	API    string      `json:"api"`
+	URL    string      `json:"url"`
+	Season int         `json:"season"`
+	Races  RaceResults `json:"races"`
+}
+
+// fetchRaceResults gets the race results for a round of a season
+func fetchRaceResults(season, round int) (*RaceResults, error) {
+	var resultsResp RaceResultsResponse
+	path := fmt.Sprintf("/%s/%d/race", seasonPath(season), round)
+	if err := fetchAPI(path, fmt.Sprintf("round %d race results", round), &resultsResp); err != nil {
+		return nil, err
+	}
+	return &resultsResp.Races, nil
+}
+
+// fetchSprintResults gets the sprint results for a round of a season
+func fetchSprintResults(season, round int) ([]RaceResult, error) {
+	var resultsResp RaceResultsResponse
+	path := fmt.Sprintf("/%s/%d/sprint/race", seasonPath(season), round)
+	if err := fetchAPI(path, fmt.Sprintf("round %d sprint results", round), &resultsResp); err != nil {
+		return nil, err
+	}
+	if len(resultsResp.Races.SprintResults) > 0 {
+		return resultsResp.Races.SprintResults, nil
+	}
+	return resultsResp.Races.Results, nil
+}
+
+// standingsAsOf rebuilds the championship standings after a round by adding up the race and sprint results
+// of every round up to it, since the API only has standings for the end of a season or the latest round
+func standingsAsOf(season int, races []Race, asOfRound int) ([]DriverStanding, []TeamStanding, error) {
+	if asOfRound > len(races) {
+		return nil, nil, fmt.Errorf("round %d is beyond the %d races in the season", asOfRound, len(races))
+	}
+
+	drivers := map[string]*DriverStanding{}
+	teams := map[string]*TeamStanding{}
+
+	// add scores a set of results, counting wins only for the race itself
+	add := func(results []RaceResult, countWins bool) {
+		for _, result := range results {
+			driver, ok := drivers[result.Driver.DriverID]
+			if !ok {
+				driver = &DriverStanding{DriverID: result.Driver.DriverID}
+				drivers[result.Driver.DriverID] = driver
+			}
+			// Drivers are listed with the team they drove for most recently
+			driver.TeamID, driver.Driver, driver.Team = result.Team.TeamID, result.Driver.Driver, result.Team
+			driver.Points += result.Points
+
+			team, ok := teams[result.Team.TeamID]
+			if !ok {
+				team = &TeamStanding{TeamID: result.Team.TeamID, Team: result.Team}
+				teams[result.Team.TeamID] = team
+			}
+			team.Points += result.Points
+
+			if countWins && result.Position == 1 {
+				driver.Wins++
+				team.Wins++
+			}
+		}
+	}
+
+	for round := 1; round <= asOfRound; round++ {
+		results, err := fetchRaceResults(season, round)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(results.Results, true)
+
+		if races[round-1].Schedule.SprintRace.Date != "" {
+			sprint, err := fetchSprintResults(season, round)
+			if err != nil {
+				return nil, nil, err
+			}
+			add(sprint, false)
+		}
+	}
+
+	driverStandings := make([]DriverStanding, 0, len(drivers))
+	for _, driver := range drivers {
+		driverStandings = append(driverStandings, *driver)
+	}
+	sort.Slice(driverStandings, func(i, j int) bool {
+		a, b := driverStandings[i], driverStandings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.DriverID < b.DriverID
+	})
+	for i := range driverStandings {
+		driverStandings[i].Position = i + 1
+	}
+
+	teamStandings := make([]TeamStanding, 0, len(teams))
+	for _, team := range teams {
+		teamStandings = append(teamStandings, *team)
+	}
+	sort.Slice(teamStandings, func(i, j int) bool {
+		a, b := teamStandings[i], teamStandings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.TeamID < b.TeamID
+	})
+	for i := range teamStandings {
+		teamStandings[i].Position = i + 1
+	}
+
+	return driverStandings, teamStandings, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeF1API is a fake F1 API serving canned responses by path
+type fakeF1API struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	responses   map[string]string
+	unpublished map[string]bool
+	requests    map[string]int
+}
+
+// newFakeF1API starts an httptest server that serves canned F1 API responses by path, pointing apiBaseURL at it.
+// Unknown and unpublished paths get the API's not found response.
+func newFakeF1API(t *testing.T, responses map[string]string) *fakeF1API {
+	t.Helper()
+	fake := &fakeF1API{responses: responses, unpublished: map[string]bool{}, requests: map[string]int{}}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.requests[r.URL.Path]++
+
+		body, ok := fake.responses[r.URL.Path]
+		if !ok || fake.unpublished[r.URL.Path] {
+			w.WriteHeader(http.StatusNotFound)
+			body = fmt.Sprintf(`{"message": "No data found for %s", "status": 404}`, r.URL.Path)
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(fake.Close)
+
+	original := apiBaseURL
+	apiBaseURL = fake.URL
+	t.Cleanup(func() { apiBaseURL = original })
+	return fake
+}
+
+// setPublished toggles whether a path is served or not found, as results are until they're published
+func (f *fakeF1API) setPublished(path string, published bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unpublished[path] = !published
+}
+
+// requestCount returns how many times a path has been requested
+func (f *fakeF1API) requestCount(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[path]
+}
+
+// resultJSON returns a race result entry for the fake API
+func resultJSON(position int, points float64, driverID, surname, teamID string) string {
+	return fmt.Sprintf(`{"position": %d, "points": %g, "driver": {"driverId": %q, "name": "Test", "surname": %q, "shortName": %q, "nationality": "Great Britain"}, "team": {"teamId": %q, "teamName": %q}}`,
+		position, points, driverID, surname, strings.ToUpper(surname[:3]), teamID, teamID)
+}
+
+// fakeSeason2024 is a three round season with a sprint at round 2
+var fakeSeason2024 = map[string]string{
+	"/2024": `{"season": 2024, "total": 3, "races": [
+		{"raceId": "bahrain_2024", "raceName": "Bahrain Grand Prix 2024", "schedule": {"race": {"date": "2024-03-02", "time": "15:00:00Z"}, "sprintRace": {"date": null, "time": null}}, "circuit": {"circuitId": "bahrain"}},
+		{"raceId": "chinese_2024", "raceName": "Chinese Grand Prix 2024", "schedule": {"race": {"date": "2024-04-21", "time": "07:00:00Z"}, "sprintRace": {"date": "2024-04-20", "time": "03:00:00Z"}}, "circuit": {"circuitId": "shanghai"}},
+		{"raceId": "japanese_2024", "raceName": "Japanese Grand Prix 2024", "schedule": {"race": {"date": "2024-04-07", "time": "05:00:00Z"}}, "circuit": {"circuitId": "suzuka"}, "country": "Japan"}
+	]}`,
+	"/2024/1/race": `{"season": 2024, "races": {"raceId": "bahrain_2024", "results": [` +
+		resultJSON(1, 25, "max_verstappen", "Verstappen", "red_bull") + `,` +
+		resultJSON(2, 18, "norris", "Norris", "mclaren") + `,` +
+		resultJSON(3, 15, "russell", "Russell", "mercedes") + `]}}`,
+	"/2024/2/race": `{"season": 2024, "races": {"raceId": "chinese_2024", "results": [` +
+		resultJSON(1, 25, "norris", "Norris", "mclaren") + `,` +
+		resultJSON(2, 18, "russell", "Russell", "mercedes") + `,` +
+		`{"position": "NC", "points": 0, "driver": {"driverId": "max_verstappen", "surname": "Verstappen"}, "team": {"teamId": "red_bull"}}]}}`,
+	"/2024/2/sprint/race": `{"season": 2024, "races": {"raceId": "chinese_2024", "sprintRaceResults": [` +
+		resultJSON(1, 8, "max_verstappen", "Verstappen", "red_bull") + `,` +
+		resultJSON(2, 7, "russell", "Russell", "mercedes") + `]}}`,
+	"/2024/drivers-championship":      `{"season": 2024, "drivers_championship": [{"driverId": "max_verstappen", "teamId": "red_bull", "points": 437, "position": 1}]}`,
+	"/2024/constructors-championship": `{"season": 2024, "constructors_championship": [{"teamId": "mclaren", "points": 666, "position": 1}]}`,
+}
+
+func TestStandingsAsOf(t *testing.T) {
+	newFakeF1API(t, fakeSeason2024)
+
+	calendar, err := fetchCalendar(2024)
+	if err != nil {
+		t.Fatalf("fetchCalendar failed: %v", err)
+	}
+	drivers, teams, err := standingsAsOf(2024, calendar.Races, 2)
+	if err != nil {
+		t.Fatalf("standingsAsOf failed: %v", err)
+	}
+
+	// Norris 18+25, Russell 15+18+7, Verstappen 25+0+8
+	wantDrivers := []struct {
+		id     string
+		points float64
+		wins   int
+	}{{"norris", 43, 1}, {"russell", 40, 0}, {"max_verstappen", 33, 1}}
+	if len(drivers) != len(wantDrivers) {
+		t.Fatalf("Expected %d drivers, got %+v", len(wantDrivers), drivers)
+	}
+	for i, want := range wantDrivers {
+		got := drivers[i]
+		if got.DriverID != want.id || got.Points != want.points || got.Wins != want.wins || got.Position != i+1 {
+			t.Errorf("Driver P%d = %s %.0f points %d wins, want %+v", i+1, got.DriverID, got.Points, got.Wins, want)
+		}
+	}
+	if drivers[0].Driver.Surname != "Norris" || drivers[0].TeamID != "mclaren" {
+		t.Errorf("Driver details should come from the results, got %+v", drivers[0])
+	}
+
+	if teams[0].TeamID != "mclaren" || teams[0].Points != 43 || teams[2].TeamID != "red_bull" {
+		t.Errorf("Unexpected team standings: %+v", teams)
+	}
+}
+
+func TestFetchTopicDataAsOfRound(t *testing.T) {
+	newFakeF1API(t, fakeSeason2024)
+	defer func() { snapshot = Snapshot{} }()
+	snapshot = Snapshot{Season: 2024, AsOfRound: 2}
+
+	data := fetchTopicData()
+	if data.Season != 2024 || data.TotalRaces != 3 || data.Round != 3 {
+		t.Errorf("Expected round 3 of 3 in 2024, got season %d round %d of %d", data.Season, data.Round, data.TotalRaces)
+	}
+	if data.NextRaceErr != nil || data.NextRace.RaceID != "japanese_2024" {
+		t.Errorf("Next race should be the round after, got %+v (%v)", data.NextRace, data.NextRaceErr)
+	}
+	if data.DriversErr != nil || data.Drivers[0].DriverID != "norris" {
+		t.Errorf("Standings should be as of round 2, got %+v (%v)", data.Drivers, data.DriversErr)
+	}
+
+	topic := slackRenderer{}.Render(data)
+	if !strings.HasPrefix(topic, ":f1: 2024 Next: R3/3 Japan :flag-jp:") {
+		t.Errorf("Unexpected topic: %s", topic)
+	}
+}
+
+func TestFetchAPINoData(t *testing.T) {
+	newFakeF1API(t, map[string]string{})
+
+	var resp RaceResultsResponse
+	err := fetchAPI("/2025/99/race", "round 99 race results", &resp)
+	if !errors.Is(err, errNoData) {
+		t.Errorf("Expected an errNoData error for a 404 response, got %v", err)
+	}
+}
+
+func TestFetchTopicDataPastSeason(t *testing.T) {
+	newFakeF1API(t, fakeSeason2024)
+	defer func() { snapshot = Snapshot{} }()
+	snapshot = Snapshot{Season: 2024}
+
+	data := fetchTopicData()
+	if data.NextRaceErr == nil {
+		t.Errorf("A finished season should have no next race, got %+v", data.NextRace)
+	}
+	if data.OffSeason {
+		t.Error("A past season should show its final standings, not the off-season countdown")
+	}
+	if data.DriversErr != nil || data.Drivers[0].Points != 437 || data.Teams[0].TeamID != "mclaren" {
+		t.Errorf("Expected the final standings, got %+v %+v", data.Drivers, data.Teams)
+	}
+}
+
+func TestFetchTopicDataUsesAPISeason(t *testing.T) {
+	// In January the API is still on last season, which the topic should show instead of the new year
+	newFakeF1API(t, map[string]string{
+		"/current":      `{"season": 2025, "races": [{"raceId": "abu_dhabi_2025", "schedule": {"race": {"date": "2025-12-07"}}}]}`,
+		"/current/next": `{"message": "No upcoming races", "status": 404}`,
+	})
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC) }
+
+	if data := fetchTopicData(); data.Season != 2025 || data.TotalRaces != 1 {
+		t.Errorf("Expected the API's 2025 season, got %d with %d races", data.Season, data.TotalRaces)
+	}
+}
+
+func TestNextRaceAfter(t *testing.T) {
+	races := []Race{
+		{RaceID: "one", Schedule: Schedule{Race: TimeInfo{Date: "2024-03-02"}}},
+		{RaceID: "two", Schedule: Schedule{Race: TimeInfo{Date: "2024-04-21"}}},
+	}
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC) }
+
+	if race, round, err := nextRaceAfter(races, 0); err != nil || race.RaceID != "two" || round != 2 {
+		t.Errorf("Expected the first race still to come, got %+v round %d (%v)", race, round, err)
+	}
+	if race, round, err := nextRaceAfter(races, 1); err != nil || race.RaceID != "two" || round != 2 {
+		t.Errorf("Expected the round after 1, got %+v round %d (%v)", race, round, err)
+	}
+	if _, _, err := nextRaceAfter(races, 2); err == nil {
+		t.Error("Expected no next race after the final round")
+	}
+	if _, _, err := nextRaceAfter(races, 3); err == nil {
+		t.Error("Expected an error for a round beyond the season")
+	}
+}
+
+func TestRoundLabel(t *testing.T) {
+	if got := roundLabel(3, 24); got != "R3/24" {
+		t.Errorf("roundLabel(3, 24) = %q", got)
+	}
+	if got := roundLabel(3, 0); got != "R3" {
+		t.Errorf("roundLabel(3, 0) = %q", got)
+	}
+}
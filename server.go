@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// topicServer serves the rendered topic, data and calendar from a cache refreshed in the background,
+// so requests never reach the F1 API
+type topicServer struct {
+	// fetch gets fresh data, fetchTopicData outside of tests
+	fetch func() *TopicData
+	// signingSecret verifies Slack requests, the Slack endpoints are disabled without it
+	signingSecret string
+	// slack answers mentions, the Events API endpoint is disabled without it
+	slack *slackClient
+	// spawn runs work after a request has been answered, in a goroutine outside of tests
+	spawn func(func())
+
+	mu              sync.RWMutex
+	data            *TopicData
+	refreshedAt     time.Time
+	refreshes       int
+	refreshFailures int
+	requests        map[string]int
+}
+
+// newTopicServer returns a server that fills its cache with fetch
+func newTopicServer(fetch func() *TopicData) *topicServer {
+	return &topicServer{
+		fetch:    fetch,
+		requests: map[string]int{},
+		spawn:    func(f func()) { go f() },
+	}
+}
+
+// refresh fetches fresh data into the cache. Data missing the standings is only used while the cache is
+// empty, so a flaky API doesn't replace good data with errors.
+func (s *topicServer) refresh() {
+	data := s.fetch()
+	failed := data.DriversErr != nil || data.TeamsErr != nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshes++
+	if failed {
+		s.refreshFailures++
+		if s.data != nil {
+			log.Printf("Refresh failed, keeping data from %s", s.refreshedAt.Format(time.RFC3339))
+			return
+		}
+	}
+	s.data = data
+	s.refreshedAt = now()
+}
+
+// run refreshes the cache every interval until stop is closed
+func (s *topicServer) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// cached returns the cached data, counting the request against its path
+func (s *topicServer) cached(path string) (*TopicData, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[path]++
+	return s.data, s.refreshedAt
+}
+
+// current returns the cached data without counting a request
+func (s *topicServer) current() *TopicData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// handler returns the server's routes
+func (s *topicServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/topic", s.text("/topic", slackRenderer{}))
+	mux.HandleFunc("/detailed", s.text("/detailed", textRenderer{}))
+	mux.HandleFunc("/data.json", s.dataJSON)
+	mux.HandleFunc("/calendar.ics", s.calendarICS)
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/metrics", s.metrics)
+	if s.signingSecret != "" {
+		mux.HandleFunc("/slack/commands", s.slashCommand)
+		if s.slack != nil {
+			mux.HandleFunc("/slack/events", s.slackEvents)
+		}
+	}
+	return mux
+}
+
+// text serves the cached data through a renderer as plain text
+func (s *topicServer) text(path string, renderer Renderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, _ := s.cached(path)
+		if data == nil {
+			http.Error(w, "no data yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, renderer.Render(data))
+	}
+}
+
+// errorString returns an error's message, or "" for nil, so errors survive JSON encoding
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// topicJSON is the JSON form of TopicData served at /data.json
+type topicJSON struct {
+	Season          int              `json:"season"`
+	TotalRaces      int              `json:"totalRaces"`
+	Round           int              `json:"round,omitempty"`
+	NextRace        *Race            `json:"nextRace,omitempty"`
+	NextRaceError   string           `json:"nextRaceError,omitempty"`
+	Drivers         []DriverStanding `json:"drivers"`
+	DriversError    string           `json:"driversError,omitempty"`
+	Teams           []TeamStanding   `json:"teams"`
+	TeamsError      string           `json:"teamsError,omitempty"`
+	Fantasy         []FantasyEntry   `json:"fantasy,omitempty"`
+	OffSeason       bool             `json:"offSeason"`
+	SeasonOpener    *Race            `json:"seasonOpener,omitempty"`
+	CalendarChanges []string         `json:"calendarChanges,omitempty"`
+	RefreshedAt     time.Time        `json:"refreshedAt"`
+}
+
+// dataJSON serves the cached data as JSON
+func (s *topicServer) dataJSON(w http.ResponseWriter, r *http.Request) {
+	data, refreshedAt := s.cached("/data.json")
+	if data == nil {
+		http.Error(w, "no data yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	out := topicJSON{
+		Season:        data.Season,
+		TotalRaces:    data.TotalRaces,
+		Round:         data.Round,
+		NextRace:      data.NextRace,
+		NextRaceError: errorString(data.NextRaceErr),
+		Drivers:       data.Drivers,
+		DriversError:  errorString(data.DriversErr),
+		Teams:         data.Teams,
+		TeamsError:    errorString(data.TeamsErr),
+		Fantasy:       data.Fantasy,
+		OffSeason:     data.OffSeason,
+		SeasonOpener:  data.SeasonOpener,
+		RefreshedAt:   refreshedAt.UTC(),
+	}
+	for _, change := range data.CalendarChanges {
+		out.CalendarChanges = append(out.CalendarChanges, change.String())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// calendarICS serves the cached season calendar as iCalendar
+func (s *topicServer) calendarICS(w http.ResponseWriter, r *http.Request) {
+	data, _ := s.cached("/calendar.ics")
+	if data == nil || len(data.Calendar) == 0 {
+		http.Error(w, "no calendar yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	io.WriteString(w, buildICS(fmt.Sprintf("F1 %d", data.Season), data.Calendar))
+}
+
+// healthz reports whether the cache has data
+func (s *topicServer) healthz(w http.ResponseWriter, r *http.Request) {
+	data, refreshedAt := s.cached("/healthz")
+	if data == nil {
+		http.Error(w, "no data yet", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintf(w, "ok, refreshed %s\n", refreshedAt.UTC().Format(time.RFC3339))
+}
+
+// metrics serves counters in the Prometheus text format
+func (s *topicServer) metrics(w http.ResponseWriter, r *http.Request) {
+	s.cached("/metrics")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP f1_topic_refreshes_total Cache refreshes from the F1 API.")
+	fmt.Fprintln(w, "# TYPE f1_topic_refreshes_total counter")
+	fmt.Fprintf(w, "f1_topic_refreshes_total %d\n", s.refreshes)
+	fmt.Fprintln(w, "# HELP f1_topic_refresh_failures_total Cache refreshes missing standings.")
+	fmt.Fprintln(w, "# TYPE f1_topic_refresh_failures_total counter")
+	fmt.Fprintf(w, "f1_topic_refresh_failures_total %d\n", s.refreshFailures)
+	fmt.Fprintln(w, "# HELP f1_topic_last_refresh_timestamp_seconds When the cached data was fetched.")
+	fmt.Fprintln(w, "# TYPE f1_topic_last_refresh_timestamp_seconds gauge")
+	var refreshed int64
+	if !s.refreshedAt.IsZero() {
+		refreshed = s.refreshedAt.Unix()
+	}
+	fmt.Fprintf(w, "f1_topic_last_refresh_timestamp_seconds %d\n", refreshed)
+	fmt.Fprintln(w, "# HELP f1_topic_requests_total HTTP requests by path.")
+	fmt.Fprintln(w, "# TYPE f1_topic_requests_total counter")
+
+	paths := make([]string, 0, len(s.requests))
+	for path := range s.requests {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(w, "f1_topic_requests_total{path=%q} %d\n", path, s.requests[path])
+	}
+}
+
+// serveCommand runs the HTTP server, refreshing the cache in the background
+func serveCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	interval := fs.Duration("refresh", 15*time.Minute, "How often to refresh the data from the F1 API")
+	secret := fs.String("signing-secret", "", "Slack signing secret enabling /slack/commands (defaults to $SLACK_SIGNING_SECRET)")
+	token := fs.String("token", "", "Slack bot token enabling /slack/events with the signing secret (defaults to $SLACK_TOKEN)")
+	remindChannel := fs.String("remind-channel", "", "Slack channel ID to post reminders before each session to (needs -token)")
+	summaryChannel := fs.String("summary-channel", "", "Slack channel ID to post a summary to after each race (needs -token)")
+	summaryWait := fs.Duration("summary-max-wait", 12*time.Hour, "How long after a race starts to keep polling for its results")
+	fs.Parse(args)
+
+	if *interval <= 0 {
+		return fmt.Errorf("-refresh must be positive, got %s", *interval)
+	}
+
+	// /detailed and /f1 show the circuit card, so fetch it with each refresh rather than per request.
+	// Calendar changes stay in /data.json until a run with -calendar-notice reports them
+	server := newTopicServer(func() *TopicData {
+		data := fetchTopicData()
+		fetchCircuitDetails(data)
+		reportCalendarChanges(data, false, PublishOptions{})
+		return data
+	})
+	server.signingSecret = signingSecret(*secret)
+	if botToken, err := slackToken(*token); err == nil {
+		server.slack = newSlackClient(botToken)
+	}
+	server.refresh()
+	go server.run(*interval, nil)
+
+	if *remindChannel != "" {
+		client, err := channelClient(PublishOptions{Channel: *remindChannel, Token: *token})
+		if err != nil {
+			return err
+		}
+		poster, err := newReminderPoster(client, *remindChannel)
+		if err != nil {
+			return err
+		}
+		go runChecks("reminders", poster.check, server.current, time.Minute, nil)
+	}
+
+	if *summaryChannel != "" {
+		client, err := channelClient(PublishOptions{Channel: *summaryChannel, Token: *token})
+		if err != nil {
+			return err
+		}
+		poster, err := newSummaryPoster(client, *summaryChannel, *summaryWait)
+		if err != nil {
+			return err
+		}
+		go runChecks("race summary", poster.check, server.current, 5*time.Minute, nil)
+	}
+
+	log.Printf("Serving on %s, refreshing every %s", *addr, *interval)
+	return http.ListenAndServe(*addr, server.handler())
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer starts the topic server on httptest with a fetch that counts its calls
+func newTestServer(t *testing.T, fetch func() *TopicData) (*topicServer, *httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	server := newTopicServer(func() *TopicData {
+		calls++
+		return fetch()
+	})
+	httpServer := httptest.NewServer(server.handler())
+	t.Cleanup(httpServer.Close)
+	return server, httpServer, &calls
+}
+
+// get fetches a path from the test server, returning the status and body
+func get(t *testing.T, server *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// testTopicDataWithCalendar returns the test data with a one race calendar
+func testTopicDataWithCalendar() *TopicData {
+	data := testTopicData()
+	data.Calendar = []Race{*data.NextRace}
+	return data
+}
+
+func TestTopicServerEndpoints(t *testing.T) {
+	fixClock(t, time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC))
+	server, httpServer, calls := newTestServer(t, testTopicDataWithCalendar)
+	server.refresh()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/topic", ":f1: 2025 Next: R3/24 Japan :flag-jp:"},
+		{"/detailed", "Next Race: Lenovo Japanese Grand Prix 2025 (Round 3)"},
+		{"/data.json", `"season":2025`},
+		{"/calendar.ics", "UID:japanese_2025-race@just-vibes-f1-slack-topic"},
+		{"/healthz", "ok, refreshed 2025-04-01T12:00:00Z"},
+	}
+	for _, test := range tests {
+		status, body := get(t, httpServer, test.path)
+		if status != http.StatusOK || !strings.Contains(body, test.want) {
+			t.Errorf("GET %s = %d, should contain %q, got:\n%s", test.path, status, test.want, body)
+		}
+	}
+
+	if *calls != 1 {
+		t.Errorf("Requests should be served from the cache, fetched %d times", *calls)
+	}
+
+	_, metrics := get(t, httpServer, "/metrics")
+	for _, want := range []string{
+		"f1_topic_refreshes_total 1\n",
+		"f1_topic_last_refresh_timestamp_seconds 1743508800\n",
+		`f1_topic_requests_total{path="/topic"} 1`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("Metrics should contain %q, got:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestTopicServerDataJSON(t *testing.T) {
+	data := testTopicData()
+	data.NextRaceErr = errors.New("no upcoming races found")
+	server, httpServer, _ := newTestServer(t, func() *TopicData { return data })
+	server.refresh()
+
+	_, body := get(t, httpServer, "/data.json")
+	var out topicJSON
+	if err := json.Unmarshal([]byte(body), &out); err != nil {
+		t.Fatalf("/data.json should be JSON: %v", err)
+	}
+	if out.NextRaceError != "no upcoming races found" || len(out.Drivers) != 3 || out.Drivers[0].DriverID != "norris" {
+		t.Errorf("Unexpected data: %+v", out)
+	}
+}
+
+func TestTopicServerEmpty(t *testing.T) {
+	_, httpServer, _ := newTestServer(t, testTopicData)
+
+	for _, path := range []string{"/topic", "/data.json", "/calendar.ics", "/healthz"} {
+		if status, _ := get(t, httpServer, path); status != http.StatusServiceUnavailable {
+			t.Errorf("GET %s before the first refresh = %d, want 503", path, status)
+		}
+	}
+}
+
+func TestTopicServerKeepsDataOnFailedRefresh(t *testing.T) {
+	fail := false
+	server, httpServer, _ := newTestServer(t, func() *TopicData {
+		data := testTopicData()
+		if fail {
+			data.DriversErr = errors.New("upstream down")
+		}
+		return data
+	})
+	server.refresh()
+	fail = true
+	server.refresh()
+
+	if _, body := get(t, httpServer, "/topic"); !strings.Contains(body, ":f1ln:NOR") {
+		t.Errorf("A failed refresh should keep the cached standings, got %s", body)
+	}
+	if _, metrics := get(t, httpServer, "/metrics"); !strings.Contains(metrics, "f1_topic_refresh_failures_total 1\n") {
+		t.Errorf("Metrics should count the failure, got:\n%s", metrics)
+	}
+}
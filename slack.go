@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Slack Web API base URL
+var slackAPIURL = "https://slack.com/api"
+
+// slackClient calls Slack Web API methods using a bot or user token
+type slackClient struct {
+	token   string
+	baseURL string
+}
+
+// newSlackClient creates a Slack Web API client for the given token
+func newSlackClient(token string) *slackClient {
+	return &slackClient{token: token, baseURL: slackAPIURL}
+}
+
+// slackResponse is the envelope common to every Slack Web API response
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// call POSTs a JSON payload to a Slack Web API method and decodes the response into out
+func (c *slackClient) call(method string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s request: %v", method, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %v", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return c.do(method, req, out)
+}
+
+// callForm POSTs form-encoded arguments to a Slack Web API method, for the read methods that don't accept JSON
+func (c *slackClient) callForm(method string, args url.Values, out any) error {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/"+method, strings.NewReader(args.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %v", method, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.do(method, req, out)
+}
+
+// do sends an authorized request to a Slack Web API method and decodes the response into out
+func (c *slackClient) do(method string, req *http.Request, out any) error {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	log.Printf("Calling Slack API method: %s", method)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %v", method, err)
+	}
+
+	var slackResp slackResponse
+	if err := json.Unmarshal(respBody, &slackResp); err != nil {
+		return fmt.Errorf("error unmarshaling %s response (HTTP %d): %v", method, resp.StatusCode, err)
+	}
+	if !slackResp.OK {
+		return fmt.Errorf("slack %s failed: %s", method, slackResp.Error)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("error unmarshaling %s response: %v", method, err)
+		}
+	}
+	return nil
+}
+
+// PostMessage posts a message to a channel with chat.postMessage
+func (c *slackClient) PostMessage(channel string, msg slackMessage) error {
+	msg.Channel = channel
+	return c.call("chat.postMessage", msg, nil)
+}
+
+// SetTopic sets a channel's topic with conversations.setTopic
+func (c *slackClient) SetTopic(channel, topic string) error {
+	return c.call("conversations.setTopic", map[string]string{"channel": channel, "topic": topic}, nil)
+}
+
+// slackMessage is a Slack message payload with optional Block Kit blocks
+type slackMessage struct {
+	Channel  string       `json:"channel,omitempty"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	Text     string       `json:"text"`
+	Blocks   []slackBlock `json:"blocks,omitempty"`
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// blockKitStandingsLimit is how many championship positions the Block Kit message shows
+const blockKitStandingsLimit = 5
+
+// blockKitRenderer renders a Slack Block Kit message payload as JSON
+type blockKitRenderer struct {
+	flags flagStyle
+}
+
+// withFlags returns a copy of the renderer using a different flag style
+func (r blockKitRenderer) withFlags(style flagStyle) Renderer {
+	r.flags = style
+	return r
+}
+
+// Render builds the Block Kit payload as indented JSON
+func (r blockKitRenderer) Render(data *TopicData) string {
+	payload, err := json.MarshalIndent(r.Message(data), "", "  ")
+	if err != nil {
+		log.Printf("Error marshaling Block Kit message: %v", err)
+		return "{}"
+	}
+	return string(payload)
+}
+
+// Message builds the Block Kit message for the next race and championship standings
+func (r blockKitRenderer) Message(data *TopicData) slackMessage {
+	emoji := emojiSet{flags: r.flags.or(shortcodeFlags), custom: true}
+
+	// Notifications and clients without Block Kit support show the fallback text instead
+	msg := slackMessage{Text: fmt.Sprintf("F1 %d", data.Season)}
+	switch {
+	case data.OffSeason:
+		msg.Text = fmt.Sprintf("F1 %d: the season is over", data.Season)
+	case data.NextRaceErr == nil:
+		msg.Text = fmt.Sprintf("F1 %d: %s (Round %d)", data.Season, data.NextRace.RaceName, data.Round)
+	}
+
+	// Header with the race name and flag, or the champions and season opener in the off-season
+	if data.OffSeason {
+		var fields []slackText
+		for _, fact := range offSeasonFacts(data, emoji) {
+			fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", fact.Label, fact.Value)})
+		}
+		msg.Blocks = append(msg.Blocks,
+			slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: fmt.Sprintf("%s The %d season is over", emoji.f1(), data.Season), Emoji: true}},
+			slackBlock{Type: "section", Fields: fields},
+		)
+	} else if data.NextRaceErr != nil {
+		msg.Blocks = append(msg.Blocks,
+			slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: fmt.Sprintf("%s %d", emoji.f1(), data.Season), Emoji: true}},
+			slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "_No upcoming races_"}},
+		)
+	} else {
+		race := data.NextRace
+		msg.Blocks = append(msg.Blocks,
+			slackBlock{Type: "header", Text: &slackText{
+				Type:  "plain_text",
+				Text:  fmt.Sprintf("%s %s", race.RaceName, emoji.raceFlag(race)),
+				Emoji: true,
+			}},
+			slackBlock{Type: "section", Text: &slackText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("Round %d · %s, %s", data.Round, race.Circuit.CircuitName, race.Country),
+			}},
+		)
+
+		// Section fields for session times
+		var fields []slackText
+		if qualy := slackSessionTime(race.Schedule.Qualy); qualy != "" {
+			fields = append(fields, slackText{Type: "mrkdwn", Text: "*Qualifying*\n" + qualy})
+		}
+		if raceTime := slackSessionTime(race.Schedule.Race); raceTime != "" {
+			fields = append(fields, slackText{Type: "mrkdwn", Text: "*Race*\n" + raceTime})
+		}
+		if len(fields) > 0 {
+			msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Fields: fields})
+		}
+	}
+
+	msg.Blocks = append(msg.Blocks, slackBlock{Type: "divider"})
+
+	// Context blocks for the top of each championship
+	if data.DriversErr == nil && len(data.Drivers) > 0 {
+		elements := []slackText{{Type: "mrkdwn", Text: "*Drivers*"}}
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Drivers); i++ {
+			driver := data.Drivers[i]
+			elements = append(elements, slackText{Type: "mrkdwn", Text: fmt.Sprintf("%d. %s*%s* %s %.0f",
+				driver.Position,
+				emoji.driver(driver.DriverID),
+				driverAbbr(driver.Driver),
+				emoji.driverFlag(driver.Driver),
+				driver.Points)})
+		}
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: elements})
+	}
+	if data.TeamsErr == nil && len(data.Teams) > 0 {
+		elements := []slackText{{Type: "mrkdwn", Text: "*Constructors*"}}
+		for i := 0; i < blockKitStandingsLimit && i < len(data.Teams); i++ {
+			team := data.Teams[i]
+			elements = append(elements, slackText{Type: "mrkdwn", Text: fmt.Sprintf("%d. %s*%s* %.0f",
+				team.Position,
+				emoji.team(team.TeamID),
+				teamAbbr(team),
+				team.Points)})
+		}
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: elements})
+	}
+
+	return msg
+}
+
+// slackSessionTime formats a session time with Slack's date formatting so each reader sees their own timezone
+func slackSessionTime(info TimeInfo) string {
+	t, hasTime, err := parseSessionTime(info)
+	if err != nil {
+		return ""
+	}
+	if !hasTime {
+		return t.Format("Mon Jan 2")
+	}
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} at {time}|%s>", t.Unix(), t.Format("Mon Jan 2 15:04 UTC"))
+}
+
+// slackToken returns the Slack token from the flag value, falling back to the SLACK_TOKEN environment variable
+func slackToken(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if token := os.Getenv("SLACK_TOKEN"); token != "" {
+		return token, nil
+	}
+	return "", errors.New("no Slack token: set -token or SLACK_TOKEN")
+}
+
+// postCommand posts the Block Kit message to a channel with chat.postMessage
+func postCommand(args []string) error {
+	fs := flag.NewFlagSet("post", flag.ExitOnError)
+	addSlackFlags(fs)
+	fs.Parse(args)
+
+	publisher, err := newAPIPublisher(blockKitRenderer{}, publishOpts)
+	if err != nil {
+		return err
+	}
+	return publisher.Publish(fetchTopicData())
+}
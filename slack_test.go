@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeSlack is a local stand-in for the Slack Web API that records requests
+type fakeSlack struct {
+	*httptest.Server
+	requests map[string][]byte
+	auth     map[string]string
+	// responses overrides the JSON response body for a method, defaulting to {"ok":true}
+	responses map[string]string
+}
+
+// newFakeSlack starts a fake Slack Web API and points slackAPIURL at it for the duration of the test
+func newFakeSlack(t *testing.T) *fakeSlack {
+	t.Helper()
+	fake := &fakeSlack{
+		requests:  map[string][]byte{},
+		auth:      map[string]string{},
+		responses: map[string]string{},
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := strings.TrimPrefix(r.URL.Path, "/")
+		body, _ := io.ReadAll(r.Body)
+		fake.requests[method] = body
+		fake.auth[method] = r.Header.Get("Authorization")
+
+		w.Header().Set("Content-Type", "application/json")
+		if resp, ok := fake.responses[method]; ok {
+			io.WriteString(w, resp)
+			return
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(fake.Close)
+
+	oldURL := slackAPIURL
+	slackAPIURL = fake.URL
+	t.Cleanup(func() { slackAPIURL = oldURL })
+
+	return fake
+}
+
+func TestBlockKitMessage(t *testing.T) {
+	msg := blockKitRenderer{}.Message(testTopicData())
+
+	if len(msg.Blocks) == 0 || msg.Blocks[0].Type != "header" {
+		t.Fatalf("First block should be a header, got %+v", msg.Blocks)
+	}
+	if got := msg.Blocks[0].Text.Text; got != "Lenovo Japanese Grand Prix 2025 :flag-jp:" {
+		t.Errorf("Unexpected header text: %q", got)
+	}
+
+	var fields []slackText
+	var contexts []slackBlock
+	for _, block := range msg.Blocks {
+		if block.Type == "section" && len(block.Fields) > 0 {
+			fields = block.Fields
+		}
+		if block.Type == "context" {
+			contexts = append(contexts, block)
+		}
+	}
+
+	if len(fields) != 2 {
+		t.Fatalf("Expected qualifying and race fields, got %+v", fields)
+	}
+	if !strings.HasPrefix(fields[1].Text, "*Race*\n<!date^1743915600^") {
+		t.Errorf("Race field should use Slack date formatting, got %q", fields[1].Text)
+	}
+
+	if len(contexts) != 2 {
+		t.Fatalf("Expected driver and constructor context blocks, got %d", len(contexts))
+	}
+	if got := contexts[0].Elements[1].Text; got != "1. :f1ln:*NOR* :gb: 44" {
+		t.Errorf("Unexpected driver context element: %q", got)
+	}
+	if got := contexts[1].Elements[1].Text; got != "1. :m1::f1tl:*MCL* 78" {
+		t.Errorf("Unexpected constructor context element: %q", got)
+	}
+}
+
+func TestBlockKitRendererIsJSON(t *testing.T) {
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(blockKitRenderer{}.Render(testTopicData())), &payload); err != nil {
+		t.Fatalf("Render should produce valid JSON: %v", err)
+	}
+	if _, ok := payload["blocks"]; !ok {
+		t.Error("Payload should contain blocks")
+	}
+}
+
+func TestSlackClientPostMessage(t *testing.T) {
+	fake := newFakeSlack(t)
+
+	msg := blockKitRenderer{}.Message(testTopicData())
+	if err := newSlackClient("xoxb-test").PostMessage("C123", msg); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	if got := fake.auth["chat.postMessage"]; got != "Bearer xoxb-test" {
+		t.Errorf("Unexpected Authorization header: %q", got)
+	}
+
+	var posted slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &posted); err != nil {
+		t.Fatalf("Posted body should be JSON: %v", err)
+	}
+	if posted.Channel != "C123" {
+		t.Errorf("Expected channel C123, got %q", posted.Channel)
+	}
+	if len(posted.Blocks) != len(msg.Blocks) {
+		t.Errorf("Expected %d blocks, got %d", len(msg.Blocks), len(posted.Blocks))
+	}
+}
+
+func TestSlackClientError(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.responses["chat.postMessage"] = `{"ok":false,"error":"channel_not_found"}`
+
+	err := newSlackClient("xoxb-test").PostMessage("C404", slackMessage{Text: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("Expected channel_not_found error, got %v", err)
+	}
+}
+
+func TestPostCommandUsesSharedFlags(t *testing.T) {
+	newFakeF1API(t, map[string]string{})
+	fake := newFakeSlack(t)
+	t.Cleanup(func() { publishOpts = PublishOptions{} })
+
+	// Set before the command, as the global -channel and -token flags are
+	publishOpts = PublishOptions{Channel: "C123", Token: "xoxb-global"}
+	if err := postCommand(nil); err != nil {
+		t.Fatalf("postCommand failed: %v", err)
+	}
+	if !strings.Contains(string(fake.requests["chat.postMessage"]), `"channel":"C123"`) || fake.auth["chat.postMessage"] != "Bearer xoxb-global" {
+		t.Errorf("Expected the global flags to be used, got %s with %q", fake.requests["chat.postMessage"], fake.auth["chat.postMessage"])
+	}
+
+	// The same flags given after the command override them
+	if err := postCommand([]string{"-channel", "C456", "-token", "xoxb-post"}); err != nil {
+		t.Fatalf("postCommand failed: %v", err)
+	}
+	if !strings.Contains(string(fake.requests["chat.postMessage"]), `"channel":"C456"`) || fake.auth["chat.postMessage"] != "Bearer xoxb-post" {
+		t.Errorf("Expected the command's flags to be used, got %s with %q", fake.requests["chat.postMessage"], fake.auth["chat.postMessage"])
+	}
+}
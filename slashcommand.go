@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackSignatureMaxAge is how old a signed request can be before it's rejected as a possible replay
+const slackSignatureMaxAge = 5 * time.Minute
+
+// slashCommandUsage lists the /f1 subcommands
+const slashCommandUsage = "Usage: `/f1` for everything, or `/f1 standings`, `/f1 next`, `/f1 driver <name>`, `/f1 team <name>`"
+
+// signingSecret returns the Slack signing secret from the flag value, falling back to the SLACK_SIGNING_SECRET environment variable
+func signingSecret(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv("SLACK_SIGNING_SECRET")
+}
+
+// verifySlackSignature checks a request was signed by Slack with the signing secret, as described at
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(secret string, header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return errors.New("missing Slack signature headers")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp %q", timestamp)
+	}
+	if age := now().Sub(time.Unix(unix, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("request timestamp is %s from now", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// readSignedBody reads a request body and verifies its Slack signature, writing an error response if it fails
+func readSignedBody(w http.ResponseWriter, r *http.Request, secret string) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "error reading request", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := verifySlackSignature(secret, r.Header, body); err != nil {
+		http.Error(w, "invalid signature: "+err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
+// parseSlashCommand splits slash command text into a lowercased subcommand and its argument,
+// e.g. "driver Max Verstappen" -> "driver", "Max Verstappen"
+func parseSlashCommand(text string) (string, string) {
+	command, arg, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return strings.ToLower(command), strings.TrimSpace(arg)
+}
+
+// slashCommandResponse answers a /f1 command from the data
+func slashCommandResponse(data *TopicData, text string) string {
+	emoji := emojiSet{flags: shortcodeFlags, custom: true}
+	command, arg := parseSlashCommand(text)
+
+	switch command {
+	case "":
+		return textRenderer{flags: shortcodeFlags}.Render(data)
+	case "standings":
+		return standingsText(data, emoji)
+	case "next":
+		return nextRaceText(data, emoji)
+	case "driver":
+		if arg == "" {
+			return "Which driver? e.g. `/f1 driver norris`"
+		}
+		return driverText(data, arg, emoji)
+	case "team":
+		if arg == "" {
+			return "Which team? e.g. `/f1 team ferrari`"
+		}
+		return teamText(data, arg, emoji)
+	default:
+		return slashCommandUsage
+	}
+}
+
+// standingsText lists the top of both championships in Slack mrkdwn
+func standingsText(data *TopicData, emoji emojiSet) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*%d Drivers' Championship*\n", data.Season))
+	if data.DriversErr != nil {
+		sb.WriteString(fmt.Sprintf("_%v_\n", data.DriversErr))
+	}
+	for i := 0; i < digestStandingsLimit && i < len(data.Drivers) && data.DriversErr == nil; i++ {
+		driver := data.Drivers[i]
+		sb.WriteString(fmt.Sprintf("%d. %s%s %s %s (%.0f)\n",
+			driver.Position, emoji.driver(driver.DriverID), driverAbbr(driver.Driver), emoji.driverFlag(driver.Driver), driver.Driver.Surname, driver.Points))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n*%d Constructors' Championship*\n", data.Season))
+	if data.TeamsErr != nil {
+		sb.WriteString(fmt.Sprintf("_%v_\n", data.TeamsErr))
+	}
+	for i := 0; i < digestStandingsLimit && i < len(data.Teams) && data.TeamsErr == nil; i++ {
+		team := data.Teams[i]
+		sb.WriteString(fmt.Sprintf("%d. %s%s %s (%.0f)\n",
+			team.Position, emoji.team(team.TeamID), teamAbbr(team), team.Team.TeamName, team.Points))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// nextRaceText describes the next race weekend with its session times in Slack mrkdwn
+func nextRaceText(data *TopicData, emoji emojiSet) string {
+	if data.NextRaceErr != nil || data.NextRace == nil {
+		return "No upcoming races"
+	}
+	race := data.NextRace
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*%s* %s\nRound %d · %s, %s\n", race.RaceName, emoji.raceFlag(race), data.Round, race.Circuit.CircuitName, race.Country))
+	for _, s := range raceSessions(race) {
+		sb.WriteString(fmt.Sprintf("• %s: %s\n", s.name, slackSessionTime(s.info)))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// driverText describes a driver's championship position in Slack mrkdwn
+func driverText(data *TopicData, query string, emoji emojiSet) string {
+	if data.DriversErr != nil {
+		return fmt.Sprintf("Driver standings unavailable: %v", data.DriversErr)
+	}
+	driver, err := matchDriver(data.Drivers, query)
+	if err != nil {
+		return capitalize(err.Error())
+	}
+	return fmt.Sprintf("%s%s *%s %s* %s (%s)\nP%d in the %d championship with %.0f points and %s",
+		emoji.driver(driver.DriverID), driverAbbr(driver.Driver), driver.Driver.Name, driver.Driver.Surname,
+		emoji.driverFlag(driver.Driver), driver.Team.TeamName, driver.Position, data.Season, driver.Points, plural(driver.Wins, "win"))
+}
+
+// teamText describes a team's championship position in Slack mrkdwn
+func teamText(data *TopicData, query string, emoji emojiSet) string {
+	if data.TeamsErr != nil {
+		return fmt.Sprintf("Constructor standings unavailable: %v", data.TeamsErr)
+	}
+	team, err := matchTeam(data.Teams, query)
+	if err != nil {
+		return capitalize(err.Error())
+	}
+
+	var drivers []string
+	for _, driver := range data.Drivers {
+		if driver.TeamID == team.TeamID {
+			drivers = append(drivers, fmt.Sprintf("%s (P%d, %.0f)", driver.Driver.Surname, driver.Position, driver.Points))
+		}
+	}
+	text := fmt.Sprintf("%s%s *%s*\nP%d in the %d championship with %.0f points and %s",
+		emoji.team(team.TeamID), teamAbbr(team), team.Team.TeamName, team.Position, data.Season, team.Points, plural(team.Wins, "win"))
+	if len(drivers) > 0 {
+		text += "\nDrivers: " + strings.Join(drivers, ", ")
+	}
+	return text
+}
+
+// capitalize upper-cases the first letter of a message, to reply with an error as a sentence
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// plural formats a count with a noun, e.g. "1 win" or "3 wins"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// slashResponse is the JSON reply to a slash command
+type slashResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// slashCommand handles /f1 slash commands, answering from the cache with an ephemeral response
+func (s *topicServer) slashCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := readSignedBody(w, r, s.signingSecret)
+	if !ok {
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	data, _ := s.cached("/slack/commands")
+	text := "F1 data isn't available yet, try again in a minute"
+	if data != nil {
+		text = slashCommandResponse(data, form.Get("text"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slashResponse{ResponseType: "ephemeral", Text: text})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The signed request from Slack's request verification documentation
+const (
+	recordedSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	recordedTimestamp = "1531420618"
+	recordedBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	recordedSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+// slackHeaders returns the signature headers for a request
+func slackHeaders(timestamp, signature string) http.Header {
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", signature)
+	return header
+}
+
+// signSlackRequest signs a body with the secret at the current time, as Slack would
+func signSlackRequest(secret string, body string) http.Header {
+	timestamp := fmt.Sprint(now().Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return slackHeaders(timestamp, "v0="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	fixClock(t, time.Unix(1531420618, 0).Add(time.Minute))
+
+	if err := verifySlackSignature(recordedSecret, slackHeaders(recordedTimestamp, recordedSignature), []byte(recordedBody)); err != nil {
+		t.Errorf("The recorded request should verify, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		header http.Header
+		body   string
+	}{
+		{"wrong secret", "not-the-secret", slackHeaders(recordedTimestamp, recordedSignature), recordedBody},
+		{"tampered body", recordedSecret, slackHeaders(recordedTimestamp, recordedSignature), strings.Replace(recordedBody, "text=", "text=standings", 1)},
+		{"missing headers", recordedSecret, http.Header{}, recordedBody},
+		{"bad timestamp", recordedSecret, slackHeaders("yesterday", recordedSignature), recordedBody},
+	}
+	for _, test := range tests {
+		if err := verifySlackSignature(test.secret, test.header, []byte(test.body)); err == nil {
+			t.Errorf("%s: expected verification to fail", test.name)
+		}
+	}
+
+	// A replayed request is rejected once it's too old
+	fixClock(t, time.Unix(1531420618, 0).Add(10*time.Minute))
+	if err := verifySlackSignature(recordedSecret, slackHeaders(recordedTimestamp, recordedSignature), []byte(recordedBody)); err == nil {
+		t.Error("Expected an old request to be rejected")
+	}
+}
+
+func TestParseSlashCommand(t *testing.T) {
+	tests := []struct {
+		text, command, arg string
+	}{
+		{"", "", ""},
+		{"  Standings ", "standings", ""},
+		{"driver norris", "driver", "norris"},
+		{"driver  Max Verstappen", "driver", "Max Verstappen"},
+	}
+	for _, test := range tests {
+		if command, arg := parseSlashCommand(test.text); command != test.command || arg != test.arg {
+			t.Errorf("parseSlashCommand(%q) = %q, %q, want %q, %q", test.text, command, arg, test.command, test.arg)
+		}
+	}
+}
+
+func TestSlashCommandResponse(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", "Next Race: Lenovo Japanese Grand Prix 2025 (Round 3)"},
+		{"standings", "1. :f1ln:NOR :gb: Norris (44)\n2. :f1mv:VER :flag-nl: Verstappen (36)"},
+		{"standings", "3. :f1tr:RBR Red Bull Racing (36)"},
+		{"next", "*Lenovo Japanese Grand Prix 2025* :flag-jp:\nRound 3"},
+		{"next", "• Qualifying: <!date^1743832800^{date_short_pretty} at {time}|Sat Apr 5 06:00 UTC>"},
+		{"driver norris", ":f1ln:NOR *Lando Norris* :gb: (McLaren Formula 1 Team)\nP1 in the 2025 championship with 44 points and 1 win"},
+		{"driver VER", "*Max Verstappen*"},
+		{"driver max", "*Max Verstappen*"},
+		{"driver alonso", `No driver matching "alonso"`},
+		{"team red_bull", ":f1tr:RBR *Red Bull Racing*\nP3 in the 2025 championship with 36 points and 0 wins\nDrivers: Verstappen (P2, 36)"},
+		{"team mercedes", "Drivers: Russell (P3, 35)"},
+		{"team merc", "Drivers: Russell (P3, 35)"},
+		{"team", "Which team?"},
+		{"podium", "Usage: `/f1`"},
+	}
+	for _, test := range tests {
+		if got := slashCommandResponse(testTopicData(), test.text); !strings.Contains(got, test.want) {
+			t.Errorf("/f1 %s should contain %q, got:\n%s", test.text, test.want, got)
+		}
+	}
+}
+
+func TestSlashCommandEndpoint(t *testing.T) {
+	server := newTopicServer(testTopicData)
+	server.signingSecret = "test-secret"
+	server.refresh()
+	httpServer := httptest.NewServer(server.handler())
+	t.Cleanup(httpServer.Close)
+
+	body := url.Values{"command": {"/f1"}, "text": {"driver russell"}, "user_id": {"U123"}}.Encode()
+
+	post := func(header http.Header) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, httpServer.URL+"/slack/commands", strings.NewReader(body))
+		req.Header = header
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := post(signSlackRequest("test-secret", body))
+	defer resp.Body.Close()
+	var reply slashResponse
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		t.Fatalf("Response should be JSON: %v", err)
+	}
+	if reply.ResponseType != "ephemeral" || !strings.Contains(reply.Text, "*George Russell*") {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+
+	if resp := post(signSlackRequest("wrong-secret", body)); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("An unsigned request should be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestSlashCommandDisabledWithoutSecret(t *testing.T) {
+	httpServer := httptest.NewServer(newTopicServer(testTopicData).handler())
+	t.Cleanup(httpServer.Close)
+
+	resp, err := http.Post(httpServer.URL+"/slack/commands", "application/x-www-form-urlencoded", strings.NewReader("text="))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Slash commands should be disabled without a signing secret, got %d", resp.StatusCode)
+	}
+}
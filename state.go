@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stateDir is where state such as the last-seen calendar is kept between runs, nothing is kept if empty
+var stateDir string
+
+// defaultStateDir returns the default -state-dir in the user's cache directory
+func defaultStateDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "just-vibes-f1-slack-topic")
+}
+
+// statePath returns the path of a state file
+func statePath(name string) string {
+	return filepath.Join(stateDir, name)
+}
+
+// loadState reads a JSON state file into v, naming it what in errors. It returns false if there's
+// no state directory or the file doesn't exist yet.
+func loadState(name, what string, v any) (bool, error) {
+	if stateDir == "" {
+		return false, nil
+	}
+	body, err := os.ReadFile(statePath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %v", what, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("error parsing %s %s: %v", what, statePath(name), err)
+	}
+	return true, nil
+}
+
+// saveState writes v to a JSON state file, doing nothing without a state directory
+func saveState(name, what string, v any) error {
+	if stateDir == "" {
+		return nil
+	}
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %v", what, err)
+	}
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return fmt.Errorf("error creating state directory: %v", err)
+	}
+	if err := os.WriteFile(statePath(name), body, 0o644); err != nil {
+		return fmt.Errorf("error saving %s: %v", what, err)
+	}
+	return nil
+}
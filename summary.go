@@ -0,0 +1,333 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// raceResultsDelay is how long after the start of a race to begin looking for results
+const raceResultsDelay = 2 * time.Hour
+
+// summaryGapsLimit is how many championship positions the summary shows gaps for
+const summaryGapsLimit = 5
+
+// errResultsNotPublished is returned while the API has no results for a race yet
+var errResultsNotPublished = errors.New("results not yet published")
+
+// weekendResults holds everything the summary needs for a round
+type weekendResults struct {
+	Season  int
+	Round   int
+	Race    *Race
+	Results []RaceResult
+	Sprint  []RaceResult
+	Drivers []DriverStanding
+}
+
+// latestRound returns the round of the latest race that has started, or 0 if none has
+func latestRound(races []Race) int {
+	latest := 0
+	for i, race := range races {
+		if start, _, err := parseSessionTime(race.Schedule.Race); err == nil && start.Before(now()) {
+			latest = i + 1
+		}
+	}
+	return latest
+}
+
+// fetchWeekendResults fetches a round's race and sprint results with the standings after it,
+// returning errResultsNotPublished if the race results aren't out yet. The standings are rebuilt from
+// results, as the API's only cover the latest round and lag behind its results after a race
+func fetchWeekendResults(season int, races []Race, round int) (*weekendResults, error) {
+	race := &races[round-1]
+	results, err := fetchRaceResults(season, round)
+	if err != nil {
+		if errors.Is(err, errNoData) {
+			return nil, errResultsNotPublished
+		}
+		return nil, err
+	}
+	if len(results.Results) == 0 {
+		return nil, errResultsNotPublished
+	}
+
+	weekend := &weekendResults{Season: season, Round: round, Race: race, Results: results.Results}
+	if race.Schedule.SprintRace.Date != "" {
+		if weekend.Sprint, err = fetchSprintResults(season, round); err != nil {
+			log.Printf("Error fetching sprint results, leaving them out: %v", err)
+		}
+	}
+	if weekend.Drivers, _, err = standingsAsOf(season, races, round); err != nil {
+		return nil, err
+	}
+	return weekend, nil
+}
+
+// parseLapTime parses a lap time such as "1:32.608"
+func parseLapTime(lap string) (time.Duration, bool) {
+	minutes, seconds, found := strings.Cut(lap, ":")
+	if !found {
+		minutes, seconds = "0", lap
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, false
+	}
+	s, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(m)*time.Minute + time.Duration(s*float64(time.Second)), true
+}
+
+// fastestLap returns the result with the fastest lap, or false if the results have no lap times
+func fastestLap(results []RaceResult) (RaceResult, bool) {
+	var fastest RaceResult
+	var best time.Duration
+	for _, result := range results {
+		if lap, ok := parseLapTime(result.FastLap); ok && (best == 0 || lap < best) {
+			fastest, best = result, lap
+		}
+	}
+	return fastest, best > 0
+}
+
+// didNotFinish reports whether a result is a retirement or otherwise unclassified
+func didNotFinish(result RaceResult) bool {
+	return result.Retired != "" || result.Position == 0 || strings.EqualFold(result.Time, "DNF")
+}
+
+// standingsBefore rebuilds the driver standings as they were before the weekend by taking its points off
+func standingsBefore(after []DriverStanding, weekendPoints map[string]float64) map[string]int {
+	before := make([]DriverStanding, len(after))
+	copy(before, after)
+	for i := range before {
+		before[i].Points -= weekendPoints[before[i].DriverID]
+	}
+	sort.SliceStable(before, func(i, j int) bool { return before[i].Points > before[j].Points })
+
+	positions := map[string]int{}
+	for i, driver := range before {
+		positions[driver.DriverID] = i + 1
+	}
+	return positions
+}
+
+// summaryMessage builds the post-race summary
+func summaryMessage(weekend *weekendResults) slackMessage {
+	emoji := emojiSet{flags: shortcodeFlags, custom: true}
+	race := weekend.Race
+	name := func(result RaceResult) string {
+		return emoji.driver(result.Driver.DriverID) + driverAbbr(result.Driver.Driver)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(":checkered_flag: *%s results* %s\n", race.RaceName, emoji.raceFlag(race)))
+
+	// Podium
+	var podium []string
+	for i, medal := range []string{":first_place_medal:", ":second_place_medal:", ":third_place_medal:"} {
+		if i < len(weekend.Results) {
+			podium = append(podium, medal+" "+name(weekend.Results[i]))
+		}
+	}
+	sb.WriteString(strings.Join(podium, "  ") + "\n")
+
+	if fastest, ok := fastestLap(weekend.Results); ok {
+		sb.WriteString(fmt.Sprintf(":stopwatch: Fastest lap: %s %s\n", name(fastest), fastest.FastLap))
+	}
+
+	var dnfs []string
+	for _, result := range weekend.Results {
+		if didNotFinish(result) {
+			dnfs = append(dnfs, name(result))
+		}
+	}
+	if len(dnfs) == 0 {
+		dnfs = append(dnfs, "none")
+	}
+	sb.WriteString(":x: DNFs: " + strings.Join(dnfs, ", ") + "\n")
+
+	// Points gained over the weekend, including the sprint
+	weekendPoints := map[string]float64{}
+	for _, result := range append(append([]RaceResult(nil), weekend.Results...), weekend.Sprint...) {
+		weekendPoints[result.Driver.DriverID] += result.Points
+	}
+	var scorers []string
+	for _, result := range weekend.Results {
+		if points := weekendPoints[result.Driver.DriverID]; points > 0 {
+			scorers = append(scorers, fmt.Sprintf("%s +%.0f", name(result), points))
+		}
+	}
+	if len(scorers) > 0 {
+		sb.WriteString("*Points:* " + strings.Join(scorers, ", ") + "\n")
+	}
+
+	if len(weekend.Drivers) == 0 {
+		return slackMessage{Text: strings.TrimSuffix(sb.String(), "\n")}
+	}
+
+	// Championship position changes
+	before := standingsBefore(weekend.Drivers, weekendPoints)
+	var changes []string
+	for _, driver := range weekend.Drivers {
+		previous, ok := before[driver.DriverID]
+		if !ok || previous == driver.Position {
+			continue
+		}
+		arrow := ":arrow_up:"
+		if driver.Position > previous {
+			arrow = ":arrow_down:"
+		}
+		changes = append(changes, fmt.Sprintf("%s%s %s P%d (was P%d)",
+			emoji.driver(driver.DriverID), driverAbbr(driver.Driver), arrow, driver.Position, previous))
+	}
+	if len(changes) > 0 {
+		sb.WriteString("*Championship:* " + strings.Join(changes, ", ") + "\n")
+	}
+
+	// Gaps to the leader
+	leader := weekend.Drivers[0]
+	gaps := []string{fmt.Sprintf("%s%s %.0f", emoji.driver(leader.DriverID), driverAbbr(leader.Driver), leader.Points)}
+	for i := 1; i < summaryGapsLimit && i < len(weekend.Drivers); i++ {
+		driver := weekend.Drivers[i]
+		gaps = append(gaps, fmt.Sprintf("%s%s -%.0f", emoji.driver(driver.DriverID), driverAbbr(driver.Driver), leader.Points-driver.Points))
+	}
+	sb.WriteString("*Gaps:* " + strings.Join(gaps, " · "))
+
+	return slackMessage{Text: sb.String()}
+}
+
+// summaryPoster posts each race's summary once, remembering which it has posted in the state directory
+type summaryPoster struct {
+	client  *slackClient
+	channel string
+	// maxWait caps how long after the race start results are polled for
+	maxWait time.Duration
+	posted  map[string]time.Time
+}
+
+// newSummaryPoster creates a poster using the summaries posted by earlier runs
+func newSummaryPoster(client *slackClient, channel string, maxWait time.Duration) (*summaryPoster, error) {
+	if stateDir == "" {
+		log.Printf("WARNING: no -state-dir, summaries may be posted again after a restart")
+	}
+	posted := map[string]time.Time{}
+	if _, err := loadState("summaries.json", "posted summaries", &posted); err != nil {
+		return nil, err
+	}
+	return &summaryPoster{client: client, channel: channel, maxWait: maxWait, posted: posted}, nil
+}
+
+// awaitingResults returns the race whose results should be out but haven't been posted, and its round
+func (p *summaryPoster) awaitingResults(races []Race) (*Race, int, bool) {
+	for i := len(races) - 1; i >= 0; i-- {
+		start, _, err := parseSessionTime(races[i].Schedule.Race)
+		if err != nil || now().Before(start.Add(raceResultsDelay)) {
+			continue
+		}
+		if now().After(start.Add(p.maxWait)) {
+			return nil, 0, false
+		}
+		if _, done := p.posted[races[i].RaceID]; done {
+			return nil, 0, false
+		}
+		return &races[i], i + 1, true
+	}
+	return nil, 0, false
+}
+
+// post fetches and posts a round's summary unless it's already been posted,
+// returning errResultsNotPublished if the results aren't out yet
+func (p *summaryPoster) post(season int, races []Race, round int) error {
+	race := &races[round-1]
+	if _, done := p.posted[race.RaceID]; done {
+		log.Printf("Summary for %s already posted", race.RaceID)
+		return nil
+	}
+
+	weekend, err := fetchWeekendResults(season, races, round)
+	if err != nil {
+		return err
+	}
+	if err := p.client.PostMessage(p.channel, summaryMessage(weekend)); err != nil {
+		return fmt.Errorf("error posting summary for %s: %v", race.RaceID, err)
+	}
+	log.Printf("Posted summary for %s to %s", race.RaceID, p.channel)
+
+	p.posted[race.RaceID] = now()
+	return saveState("summaries.json", "posted summaries", p.posted)
+}
+
+// check posts the summary of the latest race once its results are out, to be called on every tick of a daemon.
+// Each tick is one poll, so results are polled for until maxWait after the race start.
+func (p *summaryPoster) check(data *TopicData) error {
+	race, round, ok := p.awaitingResults(data.Calendar)
+	if !ok {
+		return nil
+	}
+	err := p.post(data.Season, data.Calendar, round)
+	if errors.Is(err, errResultsNotPublished) {
+		log.Printf("Results for %s not yet published, will try again", race.RaceID)
+		return nil
+	}
+	return err
+}
+
+// sleep waits between polls, a variable so tests don't wait
+var sleep = time.Sleep
+
+// pollSummary posts a round's summary, polling until its results are published or maxPolls is reached
+func (p *summaryPoster) pollSummary(season int, races []Race, round int, interval time.Duration, maxPolls int) error {
+	race := &races[round-1]
+	for poll := 1; ; poll++ {
+		err := p.post(season, races, round)
+		if !errors.Is(err, errResultsNotPublished) {
+			return err
+		}
+		if poll >= maxPolls {
+			return fmt.Errorf("results for %s still not published after %d polls", race.RaceID, maxPolls)
+		}
+		log.Printf("Results for %s not yet published, polling again in %s (%d/%d)", race.RaceID, interval, poll, maxPolls)
+		sleep(interval)
+	}
+}
+
+// summaryCommand posts the summary of the latest race, or the given round, once its results are published
+func summaryCommand(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	channel := fs.String("channel", "", "Slack channel ID to post the summary to")
+	token := fs.String("token", "", "Slack bot token (defaults to $SLACK_TOKEN)")
+	round := fs.Int("round", 0, "Round to summarise (defaults to the latest race that has started)")
+	interval := fs.Duration("poll", 5*time.Minute, "How often to check whether results are published")
+	maxPolls := fs.Int("max-polls", 36, "How many times to check before giving up")
+	fs.Parse(args)
+
+	client, err := channelClient(PublishOptions{Channel: *channel, Token: *token})
+	if err != nil {
+		return err
+	}
+	calendar, err := fetchCalendar(snapshot.Season)
+	if err != nil {
+		return err
+	}
+
+	if *round == 0 {
+		*round = latestRound(calendar.Races)
+	}
+	if *round < 1 || *round > len(calendar.Races) {
+		return fmt.Errorf("no race to summarise, round %d isn't in the %d calendar", *round, calendar.Season)
+	}
+
+	poster, err := newSummaryPoster(client, *channel, 0)
+	if err != nil {
+		return err
+	}
+	return poster.pollSummary(calendar.Season, calendar.Races, *round, *interval, *maxPolls)
+}
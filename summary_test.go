@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// japaneseWeekend returns results for the test data's race, after which Verstappen leads
+func japaneseWeekend() *weekendResults {
+	result := func(position int, points float64, driverID, surname, fastLap, retired string) RaceResult {
+		return RaceResult{
+			Position: finishPosition(position), Points: points, FastLap: fastLap, Retired: retired,
+			Driver: ResultDriver{DriverID: driverID, Driver: Driver{Surname: surname, ShortName: strings.ToUpper(surname[:3])}},
+		}
+	}
+	data := testTopicData()
+	return &weekendResults{
+		Season: 2025,
+		Round:  3,
+		Race:   data.NextRace,
+		Results: []RaceResult{
+			result(1, 25, "max_verstappen", "Verstappen", "1:30.965", ""),
+			result(2, 18, "norris", "Norris", "1:30.867", ""),
+			result(3, 15, "piastri", "Piastri", "1:31.102", ""),
+			result(4, 12, "russell", "Russell", "1:31.500", ""),
+			result(0, 0, "albon", "Albon", "", "Accident"),
+		},
+		Drivers: []DriverStanding{
+			{DriverID: "norris", Points: 62, Position: 1, Driver: Driver{Surname: "Norris", ShortName: "NOR"}},
+			{DriverID: "max_verstappen", Points: 61, Position: 2, Driver: Driver{Surname: "Verstappen", ShortName: "VER"}},
+			{DriverID: "piastri", Points: 49, Position: 3, Driver: Driver{Surname: "Piastri", ShortName: "PIA"}},
+			{DriverID: "russell", Points: 47, Position: 4, Driver: Driver{Surname: "Russell", ShortName: "RUS"}},
+		},
+	}
+}
+
+func TestSummaryMessage(t *testing.T) {
+	msg := summaryMessage(japaneseWeekend())
+
+	expected := ":checkered_flag: *Lenovo Japanese Grand Prix 2025 results* :flag-jp:\n" +
+		":first_place_medal: :f1mv:VER  :second_place_medal: :f1ln:NOR  :third_place_medal: :f1op:PIA\n" +
+		":stopwatch: Fastest lap: :f1ln:NOR 1:30.867\n" +
+		":x: DNFs: :f1aa:ALB\n" +
+		"*Points:* :f1mv:VER +25, :f1ln:NOR +18, :f1op:PIA +15, :f1gr:RUS +12\n" +
+		"*Championship:* :f1op:PIA :arrow_up: P3 (was P4), :f1gr:RUS :arrow_down: P4 (was P3)\n" +
+		"*Gaps:* :f1ln:NOR 62 · :f1mv:VER -1 · :f1op:PIA -13 · :f1gr:RUS -15"
+	if msg.Text != expected {
+		t.Errorf("Unexpected summary:\n got: %s\nwant: %s", msg.Text, expected)
+	}
+}
+
+func TestParseLapTime(t *testing.T) {
+	tests := map[string]time.Duration{
+		"1:30.965": 90*time.Second + 965*time.Millisecond,
+		"59.123":   59*time.Second + 123*time.Millisecond,
+	}
+	for lap, want := range tests {
+		if got, ok := parseLapTime(lap); !ok || got != want {
+			t.Errorf("parseLapTime(%q) = %s, %v, want %s", lap, got, ok, want)
+		}
+	}
+	for _, lap := range []string{"", "DNF", "x:30.1"} {
+		if _, ok := parseLapTime(lap); ok {
+			t.Errorf("parseLapTime(%q) should fail", lap)
+		}
+	}
+}
+
+// japaneseRounds returns a calendar with the test data's race as round 3
+func japaneseRounds() []Race {
+	return []Race{
+		{RaceID: "australian_2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-03-16", Time: "04:00:00Z"}}},
+		{RaceID: "chinese_2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-03-23", Time: "07:00:00Z"}}},
+		*testTopicData().NextRace,
+	}
+}
+
+// japaneseResultsAPI starts a fake F1 API for japaneseRounds whose round 3 results aren't yet published,
+// and whose standings are still those after round 2
+func japaneseResultsAPI(t *testing.T) *fakeF1API {
+	t.Helper()
+	fake := newFakeF1API(t, map[string]string{
+		"/2025/1/race": `{"season": 2025, "races": {"raceId": "australian_2025", "results": [` +
+			resultJSON(1, 25, "max_verstappen", "Verstappen", "red_bull") + `,` +
+			resultJSON(2, 18, "norris", "Norris", "mclaren") + `]}}`,
+		"/2025/2/race": `{"season": 2025, "races": {"raceId": "chinese_2025", "results": [` +
+			resultJSON(1, 25, "norris", "Norris", "mclaren") + `,` +
+			resultJSON(2, 18, "max_verstappen", "Verstappen", "red_bull") + `]}}`,
+		"/2025/3/race": `{"season": 2025, "races": {"raceId": "japanese_2025", "results": [` +
+			resultJSON(1, 25, "max_verstappen", "Verstappen", "red_bull") + `,` +
+			resultJSON(2, 18, "norris", "Norris", "mclaren") + `]}}`,
+		"/2025/drivers-championship": `{"season": 2025, "drivers_championship": [` +
+			`{"driverId": "norris", "points": 43, "position": 1}, {"driverId": "max_verstappen", "points": 43, "position": 2}]}`,
+	})
+	fake.setPublished("/2025/3/race", false)
+	return fake
+}
+
+// noSleep makes polling loops run without waiting, calling onSleep each time they would
+func noSleep(t *testing.T, onSleep func()) {
+	t.Helper()
+	sleep = func(time.Duration) { onSleep() }
+	t.Cleanup(func() { sleep = time.Sleep })
+}
+
+func TestPollSummary(t *testing.T) {
+	useStateDir(t)
+	fake := newFakeSlack(t)
+	api := japaneseResultsAPI(t)
+
+	// Results are published while waiting for the second poll
+	sleeps := 0
+	noSleep(t, func() {
+		sleeps++
+		if sleeps == 2 {
+			api.setPublished("/2025/3/race", true)
+		}
+	})
+
+	poster, err := newSummaryPoster(newSlackClient("xoxb-test"), "C123", 0)
+	if err != nil {
+		t.Fatalf("newSummaryPoster failed: %v", err)
+	}
+	races := japaneseRounds()
+	if err := poster.pollSummary(2025, races, 3, time.Minute, 5); err != nil {
+		t.Fatalf("pollSummary failed: %v", err)
+	}
+	// Three polls, then once more to rebuild the standings
+	if polls := api.requestCount("/2025/3/race"); polls != 4 {
+		t.Errorf("Expected 3 polls, got %d", polls-1)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(fake.requests["chat.postMessage"], &msg); err != nil {
+		t.Fatalf("chat.postMessage body should be JSON: %v", err)
+	}
+	if !strings.HasPrefix(msg.Text, ":checkered_flag: *Lenovo Japanese Grand Prix 2025 results*") {
+		t.Errorf("Unexpected summary: %s", msg.Text)
+	}
+
+	// A restarted poster doesn't post the same round again
+	delete(fake.requests, "chat.postMessage")
+	restarted, _ := newSummaryPoster(newSlackClient("xoxb-test"), "C123", 0)
+	if err := restarted.pollSummary(2025, races, 3, time.Minute, 5); err != nil {
+		t.Fatalf("pollSummary failed: %v", err)
+	}
+	if _, posted := fake.requests["chat.postMessage"]; posted {
+		t.Error("The summary shouldn't be posted twice for the same round")
+	}
+}
+
+func TestFetchWeekendResultsPastRound(t *testing.T) {
+	newFakeF1API(t, fakeSeason2024)
+	calendar, err := fetchCalendar(2024)
+	if err != nil {
+		t.Fatalf("fetchCalendar failed: %v", err)
+	}
+
+	// Round 1 of a finished season uses the standings after round 1, not the final table
+	weekend, err := fetchWeekendResults(2024, calendar.Races, 1)
+	if err != nil {
+		t.Fatalf("fetchWeekendResults failed: %v", err)
+	}
+	if len(weekend.Drivers) != 3 || weekend.Drivers[0].DriverID != "max_verstappen" || weekend.Drivers[0].Points != 25 {
+		t.Errorf("Expected the standings after round 1, got %+v", weekend.Drivers)
+	}
+	if msg := summaryMessage(weekend).Text; !strings.Contains(msg, "*Gaps:* :f1mv:VER 25 · :f1ln:NOR -7 · :f1gr:RUS -10") {
+		t.Errorf("Expected gaps after round 1, got:\n%s", msg)
+	}
+}
+
+func TestFetchWeekendResultsLatestRound(t *testing.T) {
+	api := japaneseResultsAPI(t)
+	api.setPublished("/2025/3/race", true)
+
+	// The API's standings don't include round 3 yet, so they're rebuilt from the results
+	weekend, err := fetchWeekendResults(2025, japaneseRounds(), 3)
+	if err != nil {
+		t.Fatalf("fetchWeekendResults failed: %v", err)
+	}
+	if msg := summaryMessage(weekend).Text; !strings.Contains(msg, "*Gaps:* :f1mv:VER 68 · :f1ln:NOR -7") {
+		t.Errorf("Expected gaps after round 3, got:\n%s", msg)
+	}
+}
+
+func TestPollSummaryGivesUp(t *testing.T) {
+	useStateDir(t)
+	newFakeSlack(t)
+	api := japaneseResultsAPI(t)
+	noSleep(t, func() {})
+
+	poster, _ := newSummaryPoster(newSlackClient("xoxb-test"), "C123", 0)
+	err := poster.pollSummary(2025, japaneseRounds(), 3, time.Minute, 4)
+	if err == nil || !strings.Contains(err.Error(), "still not published after 4 polls") {
+		t.Errorf("Expected polling to give up, got %v", err)
+	}
+	if polls := api.requestCount("/2025/3/race"); polls != 4 {
+		t.Errorf("Expected 4 polls, got %d", polls)
+	}
+}
+
+func TestSummaryPosterAwaitingResults(t *testing.T) {
+	races := []Race{
+		{RaceID: "chinese_2025", Schedule: Schedule{Race: TimeInfo{Date: "2025-03-23", Time: "07:00:00Z"}}},
+		*testTopicData().NextRace,
+	}
+	poster := &summaryPoster{maxWait: 12 * time.Hour, posted: map[string]time.Time{}}
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2025, 4, 6, 6, 0, 0, 0, time.UTC), ""},
+		{time.Date(2025, 4, 6, 7, 0, 0, 0, time.UTC), "japanese_2025"},
+		{time.Date(2025, 4, 6, 18, 0, 0, 0, time.UTC), ""},
+	}
+	for _, test := range tests {
+		fixClock(t, test.at)
+		race, round, ok := poster.awaitingResults(races)
+		got := ""
+		if ok {
+			got = race.RaceID
+			if round != 2 {
+				t.Errorf("Expected round 2, got %d", round)
+			}
+		}
+		if got != test.want {
+			t.Errorf("Awaiting results at %s = %q, want %q", test.at.Format(time.RFC3339), got, test.want)
+		}
+	}
+
+	fixClock(t, time.Date(2025, 4, 6, 7, 0, 0, 0, time.UTC))
+	poster.posted["japanese_2025"] = now()
+	if _, _, ok := poster.awaitingResults(races); ok {
+		t.Error("A posted race shouldn't be awaited again")
+	}
+}